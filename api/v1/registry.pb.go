@@ -11,6 +11,7 @@ import (
 
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	types "github.com/gogo/protobuf/types"
 	_ "github.com/golang/protobuf/ptypes/timestamp"
 	core "github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
 )
@@ -303,6 +304,12 @@ type VersionedApplicationSpec struct {
 	//	*VersionedApplicationSpec_HelmArchive
 	//	*VersionedApplicationSpec_ManifestsArchive
 	//	*VersionedApplicationSpec_InstallationSteps
+	//	*VersionedApplicationSpec_LocalChart
+	//	*VersionedApplicationSpec_LocalManifests
+	//	*VersionedApplicationSpec_HelmRepository
+	//	*VersionedApplicationSpec_OciChart
+	//	*VersionedApplicationSpec_GitChart
+	//	*VersionedApplicationSpec_InlineManifests
 	InstallationSpec isVersionedApplicationSpec_InstallationSpec `protobuf_oneof:"installation_spec"`
 	// Optional default values yaml; if none provided, chart default will be used
 	ValuesYaml string `protobuf:"bytes,30,opt,name=values_yaml,json=valuesYaml,proto3" json:"values_yaml,omitempty"`
@@ -361,11 +368,35 @@ type VersionedApplicationSpec_ManifestsArchive struct {
 type VersionedApplicationSpec_InstallationSteps struct {
 	InstallationSteps *InstallationSteps `protobuf:"bytes,16,opt,name=installation_steps,json=installationSteps,proto3,oneof" json:"installation_steps,omitempty"`
 }
+type VersionedApplicationSpec_LocalChart struct {
+	LocalChart *LocalLocation `protobuf:"bytes,17,opt,name=local_chart,json=localChart,proto3,oneof" json:"local_chart,omitempty"`
+}
+type VersionedApplicationSpec_LocalManifests struct {
+	LocalManifests *LocalLocation `protobuf:"bytes,18,opt,name=local_manifests,json=localManifests,proto3,oneof" json:"local_manifests,omitempty"`
+}
+type VersionedApplicationSpec_HelmRepository struct {
+	HelmRepository *HelmRepositoryLocation `protobuf:"bytes,19,opt,name=helm_repository,json=helmRepository,proto3,oneof" json:"helm_repository,omitempty"`
+}
+type VersionedApplicationSpec_OciChart struct {
+	OciChart *OciChartLocation `protobuf:"bytes,20,opt,name=oci_chart,json=ociChart,proto3,oneof" json:"oci_chart,omitempty"`
+}
+type VersionedApplicationSpec_GitChart struct {
+	GitChart *GitRepositoryLocation `protobuf:"bytes,21,opt,name=git_chart,json=gitChart,proto3,oneof" json:"git_chart,omitempty"`
+}
+type VersionedApplicationSpec_InlineManifests struct {
+	InlineManifests *InlineManifests `protobuf:"bytes,22,opt,name=inline_manifests,json=inlineManifests,proto3,oneof" json:"inline_manifests,omitempty"`
+}
 
 func (*VersionedApplicationSpec_GithubChart) isVersionedApplicationSpec_InstallationSpec()       {}
 func (*VersionedApplicationSpec_HelmArchive) isVersionedApplicationSpec_InstallationSpec()       {}
 func (*VersionedApplicationSpec_ManifestsArchive) isVersionedApplicationSpec_InstallationSpec()  {}
 func (*VersionedApplicationSpec_InstallationSteps) isVersionedApplicationSpec_InstallationSpec() {}
+func (*VersionedApplicationSpec_LocalChart) isVersionedApplicationSpec_InstallationSpec()        {}
+func (*VersionedApplicationSpec_LocalManifests) isVersionedApplicationSpec_InstallationSpec()    {}
+func (*VersionedApplicationSpec_HelmRepository) isVersionedApplicationSpec_InstallationSpec()    {}
+func (*VersionedApplicationSpec_OciChart) isVersionedApplicationSpec_InstallationSpec()          {}
+func (*VersionedApplicationSpec_GitChart) isVersionedApplicationSpec_InstallationSpec()          {}
+func (*VersionedApplicationSpec_InlineManifests) isVersionedApplicationSpec_InstallationSpec()   {}
 
 func (m *VersionedApplicationSpec) GetInstallationSpec() isVersionedApplicationSpec_InstallationSpec {
 	if m != nil {
@@ -416,6 +447,48 @@ func (m *VersionedApplicationSpec) GetInstallationSteps() *InstallationSteps {
 	return nil
 }
 
+func (m *VersionedApplicationSpec) GetLocalChart() *LocalLocation {
+	if x, ok := m.GetInstallationSpec().(*VersionedApplicationSpec_LocalChart); ok {
+		return x.LocalChart
+	}
+	return nil
+}
+
+func (m *VersionedApplicationSpec) GetLocalManifests() *LocalLocation {
+	if x, ok := m.GetInstallationSpec().(*VersionedApplicationSpec_LocalManifests); ok {
+		return x.LocalManifests
+	}
+	return nil
+}
+
+func (m *VersionedApplicationSpec) GetHelmRepository() *HelmRepositoryLocation {
+	if x, ok := m.GetInstallationSpec().(*VersionedApplicationSpec_HelmRepository); ok {
+		return x.HelmRepository
+	}
+	return nil
+}
+
+func (m *VersionedApplicationSpec) GetOciChart() *OciChartLocation {
+	if x, ok := m.GetInstallationSpec().(*VersionedApplicationSpec_OciChart); ok {
+		return x.OciChart
+	}
+	return nil
+}
+
+func (m *VersionedApplicationSpec) GetGitChart() *GitRepositoryLocation {
+	if x, ok := m.GetInstallationSpec().(*VersionedApplicationSpec_GitChart); ok {
+		return x.GitChart
+	}
+	return nil
+}
+
+func (m *VersionedApplicationSpec) GetInlineManifests() *InlineManifests {
+	if x, ok := m.GetInstallationSpec().(*VersionedApplicationSpec_InlineManifests); ok {
+		return x.InlineManifests
+	}
+	return nil
+}
+
 func (m *VersionedApplicationSpec) GetValuesYaml() string {
 	if m != nil {
 		return m.ValuesYaml
@@ -458,6 +531,12 @@ func (*VersionedApplicationSpec) XXX_OneofWrappers() []interface{} {
 		(*VersionedApplicationSpec_HelmArchive)(nil),
 		(*VersionedApplicationSpec_ManifestsArchive)(nil),
 		(*VersionedApplicationSpec_InstallationSteps)(nil),
+		(*VersionedApplicationSpec_LocalChart)(nil),
+		(*VersionedApplicationSpec_LocalManifests)(nil),
+		(*VersionedApplicationSpec_HelmRepository)(nil),
+		(*VersionedApplicationSpec_OciChart)(nil),
+		(*VersionedApplicationSpec_GitChart)(nil),
+		(*VersionedApplicationSpec_InlineManifests)(nil),
 	}
 }
 
@@ -514,6 +593,12 @@ type InstallationSteps_Step struct {
 	//	*InstallationSteps_Step_GithubChart
 	//	*InstallationSteps_Step_HelmArchive
 	//	*InstallationSteps_Step_ManifestsArchive
+	//	*InstallationSteps_Step_LocalChart
+	//	*InstallationSteps_Step_LocalManifests
+	//	*InstallationSteps_Step_HelmRepository
+	//	*InstallationSteps_Step_OciChart
+	//	*InstallationSteps_Step_GitChart
+	//	*InstallationSteps_Step_InlineManifests
 	Step                 isInstallationSteps_Step_Step `protobuf_oneof:"step"`
 	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
 	XXX_unrecognized     []byte                        `json:"-"`
@@ -558,10 +643,34 @@ type InstallationSteps_Step_HelmArchive struct {
 type InstallationSteps_Step_ManifestsArchive struct {
 	ManifestsArchive *TgzLocation `protobuf:"bytes,3,opt,name=manifests_archive,json=manifestsArchive,proto3,oneof" json:"manifests_archive,omitempty"`
 }
+type InstallationSteps_Step_LocalChart struct {
+	LocalChart *LocalLocation `protobuf:"bytes,5,opt,name=local_chart,json=localChart,proto3,oneof" json:"local_chart,omitempty"`
+}
+type InstallationSteps_Step_LocalManifests struct {
+	LocalManifests *LocalLocation `protobuf:"bytes,6,opt,name=local_manifests,json=localManifests,proto3,oneof" json:"local_manifests,omitempty"`
+}
+type InstallationSteps_Step_HelmRepository struct {
+	HelmRepository *HelmRepositoryLocation `protobuf:"bytes,7,opt,name=helm_repository,json=helmRepository,proto3,oneof" json:"helm_repository,omitempty"`
+}
+type InstallationSteps_Step_OciChart struct {
+	OciChart *OciChartLocation `protobuf:"bytes,8,opt,name=oci_chart,json=ociChart,proto3,oneof" json:"oci_chart,omitempty"`
+}
+type InstallationSteps_Step_GitChart struct {
+	GitChart *GitRepositoryLocation `protobuf:"bytes,9,opt,name=git_chart,json=gitChart,proto3,oneof" json:"git_chart,omitempty"`
+}
+type InstallationSteps_Step_InlineManifests struct {
+	InlineManifests *InlineManifests `protobuf:"bytes,10,opt,name=inline_manifests,json=inlineManifests,proto3,oneof" json:"inline_manifests,omitempty"`
+}
 
 func (*InstallationSteps_Step_GithubChart) isInstallationSteps_Step_Step()      {}
 func (*InstallationSteps_Step_HelmArchive) isInstallationSteps_Step_Step()      {}
 func (*InstallationSteps_Step_ManifestsArchive) isInstallationSteps_Step_Step() {}
+func (*InstallationSteps_Step_LocalChart) isInstallationSteps_Step_Step()       {}
+func (*InstallationSteps_Step_LocalManifests) isInstallationSteps_Step_Step()   {}
+func (*InstallationSteps_Step_HelmRepository) isInstallationSteps_Step_Step()   {}
+func (*InstallationSteps_Step_OciChart) isInstallationSteps_Step_Step()         {}
+func (*InstallationSteps_Step_GitChart) isInstallationSteps_Step_Step()         {}
+func (*InstallationSteps_Step_InlineManifests) isInstallationSteps_Step_Step()  {}
 
 func (m *InstallationSteps_Step) GetStep() isInstallationSteps_Step_Step {
 	if m != nil {
@@ -598,12 +707,60 @@ func (m *InstallationSteps_Step) GetManifestsArchive() *TgzLocation {
 	return nil
 }
 
+func (m *InstallationSteps_Step) GetLocalChart() *LocalLocation {
+	if x, ok := m.GetStep().(*InstallationSteps_Step_LocalChart); ok {
+		return x.LocalChart
+	}
+	return nil
+}
+
+func (m *InstallationSteps_Step) GetLocalManifests() *LocalLocation {
+	if x, ok := m.GetStep().(*InstallationSteps_Step_LocalManifests); ok {
+		return x.LocalManifests
+	}
+	return nil
+}
+
+func (m *InstallationSteps_Step) GetHelmRepository() *HelmRepositoryLocation {
+	if x, ok := m.GetStep().(*InstallationSteps_Step_HelmRepository); ok {
+		return x.HelmRepository
+	}
+	return nil
+}
+
+func (m *InstallationSteps_Step) GetOciChart() *OciChartLocation {
+	if x, ok := m.GetStep().(*InstallationSteps_Step_OciChart); ok {
+		return x.OciChart
+	}
+	return nil
+}
+
+func (m *InstallationSteps_Step) GetGitChart() *GitRepositoryLocation {
+	if x, ok := m.GetStep().(*InstallationSteps_Step_GitChart); ok {
+		return x.GitChart
+	}
+	return nil
+}
+
+func (m *InstallationSteps_Step) GetInlineManifests() *InlineManifests {
+	if x, ok := m.GetStep().(*InstallationSteps_Step_InlineManifests); ok {
+		return x.InlineManifests
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*InstallationSteps_Step) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*InstallationSteps_Step_GithubChart)(nil),
 		(*InstallationSteps_Step_HelmArchive)(nil),
 		(*InstallationSteps_Step_ManifestsArchive)(nil),
+		(*InstallationSteps_Step_LocalChart)(nil),
+		(*InstallationSteps_Step_LocalManifests)(nil),
+		(*InstallationSteps_Step_HelmRepository)(nil),
+		(*InstallationSteps_Step_OciChart)(nil),
+		(*InstallationSteps_Step_GitChart)(nil),
+		(*InstallationSteps_Step_InlineManifests)(nil),
 	}
 }
 
@@ -777,9 +934,14 @@ type LayerOption struct {
 	// Note that these resources do not factor into manifest rendering, but can be used by interfaces to the
 	// renderer to validate or create expected resources on the cluster before install.
 	ResourceDependencies []*ResourceDependency `protobuf:"bytes,7,rep,name=resource_dependencies,json=resourceDependencies,proto3" json:"resource_dependencies,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	// Optional kustomize overlay that is applied on top of the rendered installation manifest.
+	Kustomize *KustomizeOverlay `protobuf:"bytes,8,opt,name=kustomize,proto3" json:"kustomize,omitempty"`
+	// Optional patches that are applied to the rendered installation manifest, in order.
+	// Unlike helm values, patches work for every installation spec, including plain manifests.
+	Patches              []*ResourcePatch `protobuf:"bytes,9,rep,name=patches,proto3" json:"patches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *LayerOption) Reset()         { *m = LayerOption{} }
@@ -848,6 +1010,252 @@ func (m *LayerOption) GetResourceDependencies() []*ResourceDependency {
 	return nil
 }
 
+func (m *LayerOption) GetKustomize() *KustomizeOverlay {
+	if m != nil {
+		return m.Kustomize
+	}
+	return nil
+}
+
+func (m *LayerOption) GetPatches() []*ResourcePatch {
+	if m != nil {
+		return m.Patches
+	}
+	return nil
+}
+
+// A patch that is applied to every rendered resource matched by its selector.
+type ResourcePatch struct {
+	// Selects the resources to patch
+	Selector *ResourceSelector `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// Types that are valid to be assigned to Patch:
+	//	*ResourcePatch_JsonPatch
+	//	*ResourcePatch_StrategicMergePatch
+	Patch                isResourcePatch_Patch `protobuf_oneof:"patch"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ResourcePatch) Reset()         { *m = ResourcePatch{} }
+func (m *ResourcePatch) String() string { return proto.CompactTextString(m) }
+func (*ResourcePatch) ProtoMessage()    {}
+func (*ResourcePatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{7}
+}
+func (m *ResourcePatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourcePatch.Unmarshal(m, b)
+}
+func (m *ResourcePatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourcePatch.Marshal(b, m, deterministic)
+}
+func (m *ResourcePatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourcePatch.Merge(m, src)
+}
+func (m *ResourcePatch) XXX_Size() int {
+	return xxx_messageInfo_ResourcePatch.Size(m)
+}
+func (m *ResourcePatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourcePatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourcePatch proto.InternalMessageInfo
+
+type isResourcePatch_Patch interface {
+	isResourcePatch_Patch()
+	Equal(interface{}) bool
+}
+
+type ResourcePatch_JsonPatch struct {
+	JsonPatch string `protobuf:"bytes,2,opt,name=json_patch,json=jsonPatch,proto3,oneof" json:"json_patch,omitempty"`
+}
+type ResourcePatch_StrategicMergePatch struct {
+	StrategicMergePatch string `protobuf:"bytes,3,opt,name=strategic_merge_patch,json=strategicMergePatch,proto3,oneof" json:"strategic_merge_patch,omitempty"`
+}
+
+func (*ResourcePatch_JsonPatch) isResourcePatch_Patch()           {}
+func (*ResourcePatch_StrategicMergePatch) isResourcePatch_Patch() {}
+
+func (m *ResourcePatch) GetPatch() isResourcePatch_Patch {
+	if m != nil {
+		return m.Patch
+	}
+	return nil
+}
+
+func (m *ResourcePatch) GetSelector() *ResourceSelector {
+	if m != nil {
+		return m.Selector
+	}
+	return nil
+}
+
+func (m *ResourcePatch) GetJsonPatch() string {
+	if x, ok := m.GetPatch().(*ResourcePatch_JsonPatch); ok {
+		return x.JsonPatch
+	}
+	return ""
+}
+
+func (m *ResourcePatch) GetStrategicMergePatch() string {
+	if x, ok := m.GetPatch().(*ResourcePatch_StrategicMergePatch); ok {
+		return x.StrategicMergePatch
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ResourcePatch) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ResourcePatch_JsonPatch)(nil),
+		(*ResourcePatch_StrategicMergePatch)(nil),
+	}
+}
+
+// Selects rendered resources. Empty fields match any value.
+type ResourceSelector struct {
+	// API group of the resource, i.e. "apps". Use "core" to select resources in the core group.
+	Group                string   `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	Kind                 string   `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Namespace            string   `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResourceSelector) Reset()         { *m = ResourceSelector{} }
+func (m *ResourceSelector) String() string { return proto.CompactTextString(m) }
+func (*ResourceSelector) ProtoMessage()    {}
+func (*ResourceSelector) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{8}
+}
+func (m *ResourceSelector) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceSelector.Unmarshal(m, b)
+}
+func (m *ResourceSelector) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceSelector.Marshal(b, m, deterministic)
+}
+func (m *ResourceSelector) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceSelector.Merge(m, src)
+}
+func (m *ResourceSelector) XXX_Size() int {
+	return xxx_messageInfo_ResourceSelector.Size(m)
+}
+func (m *ResourceSelector) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceSelector.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourceSelector proto.InternalMessageInfo
+
+func (m *ResourceSelector) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ResourceSelector) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *ResourceSelector) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ResourceSelector) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+// A kustomize overlay that customizes the rendered installation manifest.
+// The rendered manifest is exposed to the overlay as a kustomization in the "base" directory at the root of the
+// overlay location, so an overlay can reference it with a relative path, i.e. "../base".
+// Generators of kind "ManifestRender" are rendered as go templates before the overlay is built.
+type KustomizeOverlay struct {
+	// Location of the directory containing the overlay
+	//
+	// Types that are valid to be assigned to Location:
+	//	*KustomizeOverlay_Github
+	Location isKustomizeOverlay_Location `protobuf_oneof:"location"`
+	// Path of the overlay, relative to its location
+	OverlayPath          string   `protobuf:"bytes,2,opt,name=overlay_path,json=overlayPath,proto3" json:"overlay_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KustomizeOverlay) Reset()         { *m = KustomizeOverlay{} }
+func (m *KustomizeOverlay) String() string { return proto.CompactTextString(m) }
+func (*KustomizeOverlay) ProtoMessage()    {}
+func (*KustomizeOverlay) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{9}
+}
+func (m *KustomizeOverlay) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KustomizeOverlay.Unmarshal(m, b)
+}
+func (m *KustomizeOverlay) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KustomizeOverlay.Marshal(b, m, deterministic)
+}
+func (m *KustomizeOverlay) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KustomizeOverlay.Merge(m, src)
+}
+func (m *KustomizeOverlay) XXX_Size() int {
+	return xxx_messageInfo_KustomizeOverlay.Size(m)
+}
+func (m *KustomizeOverlay) XXX_DiscardUnknown() {
+	xxx_messageInfo_KustomizeOverlay.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KustomizeOverlay proto.InternalMessageInfo
+
+type isKustomizeOverlay_Location interface {
+	isKustomizeOverlay_Location()
+	Equal(interface{}) bool
+}
+
+type KustomizeOverlay_Github struct {
+	Github *GithubRepositoryLocation `protobuf:"bytes,1,opt,name=github,proto3,oneof" json:"github,omitempty"`
+}
+
+func (*KustomizeOverlay_Github) isKustomizeOverlay_Location() {}
+
+func (m *KustomizeOverlay) GetLocation() isKustomizeOverlay_Location {
+	if m != nil {
+		return m.Location
+	}
+	return nil
+}
+
+func (m *KustomizeOverlay) GetGithub() *GithubRepositoryLocation {
+	if x, ok := m.GetLocation().(*KustomizeOverlay_Github); ok {
+		return x.Github
+	}
+	return nil
+}
+
+func (m *KustomizeOverlay) GetOverlayPath() string {
+	if m != nil {
+		return m.OverlayPath
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*KustomizeOverlay) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*KustomizeOverlay_Github)(nil),
+	}
+}
+
 // Represents a resource that must be present on a cluster for install to succeed.
 type ResourceDependency struct {
 	// Types that are valid to be assigned to Type:
@@ -862,7 +1270,7 @@ func (m *ResourceDependency) Reset()         { *m = ResourceDependency{} }
 func (m *ResourceDependency) String() string { return proto.CompactTextString(m) }
 func (*ResourceDependency) ProtoMessage()    {}
 func (*ResourceDependency) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{7}
+	return fileDescriptor_d1ad3a89626d72ea, []int{10}
 }
 func (m *ResourceDependency) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResourceDependency.Unmarshal(m, b)
@@ -930,7 +1338,7 @@ func (m *ResourceDependency_Secret) Reset()         { *m = ResourceDependency_Se
 func (m *ResourceDependency_Secret) String() string { return proto.CompactTextString(m) }
 func (*ResourceDependency_Secret) ProtoMessage()    {}
 func (*ResourceDependency_Secret) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{7, 0}
+	return fileDescriptor_d1ad3a89626d72ea, []int{10, 0}
 }
 func (m *ResourceDependency_Secret) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResourceDependency_Secret.Unmarshal(m, b)
@@ -981,17 +1389,28 @@ type Parameter struct {
 	// to be performed.
 	Required bool `protobuf:"varint,5,opt,name=required,proto3" json:"required,omitempty"`
 	// User-friendly display name of the parameter
-	DisplayName          string   `protobuf:"bytes,6,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DisplayName string `protobuf:"bytes,6,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// Constraints on the value of the parameter, enforced by the renderer. They do not apply to empty values.
+	// If set, the value must be one of these. Interfaces to the renderer offer them as choices.
+	AllowedValues []string `protobuf:"bytes,7,rep,name=allowed_values,json=allowedValues,proto3" json:"allowed_values,omitempty"`
+	// If set, the value must match this regular expression, e.g. "^[a-z0-9-]+$"
+	Pattern string `protobuf:"bytes,8,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// Inclusive bounds on the value of INT and FLOAT parameters.
+	Minimum *types.DoubleValue `protobuf:"bytes,9,opt,name=minimum,proto3" json:"minimum,omitempty"`
+	Maximum *types.DoubleValue `protobuf:"bytes,10,opt,name=maximum,proto3" json:"maximum,omitempty"`
+	// Inclusive bounds on the length of the value, in characters.
+	MinLength            *types.UInt32Value `protobuf:"bytes,11,opt,name=min_length,json=minLength,proto3" json:"min_length,omitempty"`
+	MaxLength            *types.UInt32Value `protobuf:"bytes,12,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *Parameter) Reset()         { *m = Parameter{} }
 func (m *Parameter) String() string { return proto.CompactTextString(m) }
 func (*Parameter) ProtoMessage()    {}
 func (*Parameter) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{8}
+	return fileDescriptor_d1ad3a89626d72ea, []int{11}
 }
 func (m *Parameter) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Parameter.Unmarshal(m, b)
@@ -1053,6 +1472,48 @@ func (m *Parameter) GetDisplayName() string {
 	return ""
 }
 
+func (m *Parameter) GetAllowedValues() []string {
+	if m != nil {
+		return m.AllowedValues
+	}
+	return nil
+}
+
+func (m *Parameter) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *Parameter) GetMinimum() *types.DoubleValue {
+	if m != nil {
+		return m.Minimum
+	}
+	return nil
+}
+
+func (m *Parameter) GetMaximum() *types.DoubleValue {
+	if m != nil {
+		return m.Maximum
+	}
+	return nil
+}
+
+func (m *Parameter) GetMinLength() *types.UInt32Value {
+	if m != nil {
+		return m.MinLength
+	}
+	return nil
+}
+
+func (m *Parameter) GetMaxLength() *types.UInt32Value {
+	if m != nil {
+		return m.MaxLength
+	}
+	return nil
+}
+
 // Value for a parameter.
 // Types here should be kept in sync with the ParameterType enum.
 // Note that regardless of type, parameters are passed as string helm values.
@@ -1074,7 +1535,7 @@ func (m *ParameterValue) Reset()         { *m = ParameterValue{} }
 func (m *ParameterValue) String() string { return proto.CompactTextString(m) }
 func (*ParameterValue) ProtoMessage()    {}
 func (*ParameterValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{9}
+	return fileDescriptor_d1ad3a89626d72ea, []int{12}
 }
 func (m *ParameterValue) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ParameterValue.Unmarshal(m, b)
@@ -1200,7 +1661,7 @@ func (m *SecretRef) Reset()         { *m = SecretRef{} }
 func (m *SecretRef) String() string { return proto.CompactTextString(m) }
 func (*SecretRef) ProtoMessage()    {}
 func (*SecretRef) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{10}
+	return fileDescriptor_d1ad3a89626d72ea, []int{13}
 }
 func (m *SecretRef) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SecretRef.Unmarshal(m, b)
@@ -1251,7 +1712,7 @@ func (m *SecretValue) Reset()         { *m = SecretValue{} }
 func (m *SecretValue) String() string { return proto.CompactTextString(m) }
 func (*SecretValue) ProtoMessage()    {}
 func (*SecretValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{11}
+	return fileDescriptor_d1ad3a89626d72ea, []int{14}
 }
 func (m *SecretValue) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SecretValue.Unmarshal(m, b)
@@ -1339,7 +1800,7 @@ func (m *FlavorCompatibility) Reset()         { *m = FlavorCompatibility{} }
 func (m *FlavorCompatibility) String() string { return proto.CompactTextString(m) }
 func (*FlavorCompatibility) ProtoMessage()    {}
 func (*FlavorCompatibility) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{12}
+	return fileDescriptor_d1ad3a89626d72ea, []int{15}
 }
 func (m *FlavorCompatibility) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FlavorCompatibility.Unmarshal(m, b)
@@ -1379,7 +1840,7 @@ func (m *CompatibleFlavorMeshPair) Reset()         { *m = CompatibleFlavorMeshPa
 func (m *CompatibleFlavorMeshPair) String() string { return proto.CompactTextString(m) }
 func (*CompatibleFlavorMeshPair) ProtoMessage()    {}
 func (*CompatibleFlavorMeshPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{13}
+	return fileDescriptor_d1ad3a89626d72ea, []int{16}
 }
 func (m *CompatibleFlavorMeshPair) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CompatibleFlavorMeshPair.Unmarshal(m, b)
@@ -1425,7 +1886,7 @@ func (m *RequirementSet) Reset()         { *m = RequirementSet{} }
 func (m *RequirementSet) String() string { return proto.CompactTextString(m) }
 func (*RequirementSet) ProtoMessage()    {}
 func (*RequirementSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{14}
+	return fileDescriptor_d1ad3a89626d72ea, []int{17}
 }
 func (m *RequirementSet) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RequirementSet.Unmarshal(m, b)
@@ -1467,7 +1928,7 @@ func (m *MeshRequirement) Reset()         { *m = MeshRequirement{} }
 func (m *MeshRequirement) String() string { return proto.CompactTextString(m) }
 func (*MeshRequirement) ProtoMessage()    {}
 func (*MeshRequirement) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{15}
+	return fileDescriptor_d1ad3a89626d72ea, []int{18}
 }
 func (m *MeshRequirement) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MeshRequirement.Unmarshal(m, b)
@@ -1516,7 +1977,7 @@ func (m *GithubRepositoryLocation) Reset()         { *m = GithubRepositoryLocati
 func (m *GithubRepositoryLocation) String() string { return proto.CompactTextString(m) }
 func (*GithubRepositoryLocation) ProtoMessage()    {}
 func (*GithubRepositoryLocation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{16}
+	return fileDescriptor_d1ad3a89626d72ea, []int{19}
 }
 func (m *GithubRepositoryLocation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GithubRepositoryLocation.Unmarshal(m, b)
@@ -1564,19 +2025,80 @@ func (m *GithubRepositoryLocation) GetDirectory() string {
 	return ""
 }
 
-// Location of a gzipped tar file
-type TgzLocation struct {
-	Uri                  string   `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
+// Location of a directory in a git repository hosted on any git server
+type GitRepositoryLocation struct {
+	// Url to clone the repository from, i.e. https://git.example.com/charts.git, or a path to a local repository
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Branch, tag or commit to check out
+	Ref                  string   `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`
+	Directory            string   `protobuf:"bytes,3,opt,name=directory,proto3" json:"directory,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *TgzLocation) Reset()         { *m = TgzLocation{} }
-func (m *TgzLocation) String() string { return proto.CompactTextString(m) }
-func (*TgzLocation) ProtoMessage()    {}
-func (*TgzLocation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{17}
+func (m *GitRepositoryLocation) Reset()         { *m = GitRepositoryLocation{} }
+func (m *GitRepositoryLocation) String() string { return proto.CompactTextString(m) }
+func (*GitRepositoryLocation) ProtoMessage()    {}
+func (*GitRepositoryLocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{20}
+}
+func (m *GitRepositoryLocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GitRepositoryLocation.Unmarshal(m, b)
+}
+func (m *GitRepositoryLocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GitRepositoryLocation.Marshal(b, m, deterministic)
+}
+func (m *GitRepositoryLocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GitRepositoryLocation.Merge(m, src)
+}
+func (m *GitRepositoryLocation) XXX_Size() int {
+	return xxx_messageInfo_GitRepositoryLocation.Size(m)
+}
+func (m *GitRepositoryLocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_GitRepositoryLocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GitRepositoryLocation proto.InternalMessageInfo
+
+func (m *GitRepositoryLocation) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *GitRepositoryLocation) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *GitRepositoryLocation) GetDirectory() string {
+	if m != nil {
+		return m.Directory
+	}
+	return ""
+}
+
+// Location of a gzipped tar file
+type TgzLocation struct {
+	Uri string `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
+	// If true, the manifests in a manifests archive are rendered as go templates before they are applied.
+	// Templates can reference the render inputs, i.e. {{ .InstallNamespace }}, {{ .MeshRef.Name }} or
+	// {{ .Params.myParam }}. Ignored for helm archives.
+	Template             bool     `protobuf:"varint,2,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TgzLocation) Reset()         { *m = TgzLocation{} }
+func (m *TgzLocation) String() string { return proto.CompactTextString(m) }
+func (*TgzLocation) ProtoMessage()    {}
+func (*TgzLocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{21}
 }
 func (m *TgzLocation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TgzLocation.Unmarshal(m, b)
@@ -1603,6 +2125,213 @@ func (m *TgzLocation) GetUri() string {
 	return ""
 }
 
+func (m *TgzLocation) GetTemplate() bool {
+	if m != nil {
+		return m.Template
+	}
+	return false
+}
+
+// Location of a chart in a helm repository
+type HelmRepositoryLocation struct {
+	// Url of the repository, i.e. https://kubernetes-charts.storage.googleapis.com
+	RepositoryUrl string `protobuf:"bytes,1,opt,name=repository_url,json=repositoryUrl,proto3" json:"repository_url,omitempty"`
+	// Name of the chart in the repository's index
+	Chart string `protobuf:"bytes,2,opt,name=chart,proto3" json:"chart,omitempty"`
+	// Version of the chart, or a semver constraint on it. If empty, the latest version is used.
+	Version              string   `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HelmRepositoryLocation) Reset()         { *m = HelmRepositoryLocation{} }
+func (m *HelmRepositoryLocation) String() string { return proto.CompactTextString(m) }
+func (*HelmRepositoryLocation) ProtoMessage()    {}
+func (*HelmRepositoryLocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{22}
+}
+func (m *HelmRepositoryLocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HelmRepositoryLocation.Unmarshal(m, b)
+}
+func (m *HelmRepositoryLocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HelmRepositoryLocation.Marshal(b, m, deterministic)
+}
+func (m *HelmRepositoryLocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HelmRepositoryLocation.Merge(m, src)
+}
+func (m *HelmRepositoryLocation) XXX_Size() int {
+	return xxx_messageInfo_HelmRepositoryLocation.Size(m)
+}
+func (m *HelmRepositoryLocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_HelmRepositoryLocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HelmRepositoryLocation proto.InternalMessageInfo
+
+func (m *HelmRepositoryLocation) GetRepositoryUrl() string {
+	if m != nil {
+		return m.RepositoryUrl
+	}
+	return ""
+}
+
+func (m *HelmRepositoryLocation) GetChart() string {
+	if m != nil {
+		return m.Chart
+	}
+	return ""
+}
+
+func (m *HelmRepositoryLocation) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+// Location of a helm chart stored as an OCI artifact
+type OciChartLocation struct {
+	// Reference to the artifact in a registry, i.e. registry.example.com/charts/app:1.0.0, or in a local OCI
+	// image layout directory, i.e. oci-layout:///path/to/layout:1.0.0
+	Reference string `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
+	// Optional digest of the artifact's manifest, i.e. sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b.
+	// If set, the artifact is verified against it.
+	Digest               string   `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OciChartLocation) Reset()         { *m = OciChartLocation{} }
+func (m *OciChartLocation) String() string { return proto.CompactTextString(m) }
+func (*OciChartLocation) ProtoMessage()    {}
+func (*OciChartLocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{23}
+}
+func (m *OciChartLocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OciChartLocation.Unmarshal(m, b)
+}
+func (m *OciChartLocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OciChartLocation.Marshal(b, m, deterministic)
+}
+func (m *OciChartLocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OciChartLocation.Merge(m, src)
+}
+func (m *OciChartLocation) XXX_Size() int {
+	return xxx_messageInfo_OciChartLocation.Size(m)
+}
+func (m *OciChartLocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_OciChartLocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OciChartLocation proto.InternalMessageInfo
+
+func (m *OciChartLocation) GetReference() string {
+	if m != nil {
+		return m.Reference
+	}
+	return ""
+}
+
+func (m *OciChartLocation) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+// Kubernetes manifests embedded directly in the spec, for small applications and glue resources
+type InlineManifests struct {
+	// One or more yaml documents. They are rendered as go templates before they are applied, and can reference the
+	// render inputs, i.e. {{ .InstallNamespace }}, {{ .MeshRef.Name }} or {{ .Params.myParam }}.
+	Yaml                 string   `protobuf:"bytes,1,opt,name=yaml,proto3" json:"yaml,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InlineManifests) Reset()         { *m = InlineManifests{} }
+func (m *InlineManifests) String() string { return proto.CompactTextString(m) }
+func (*InlineManifests) ProtoMessage()    {}
+func (*InlineManifests) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{24}
+}
+func (m *InlineManifests) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InlineManifests.Unmarshal(m, b)
+}
+func (m *InlineManifests) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InlineManifests.Marshal(b, m, deterministic)
+}
+func (m *InlineManifests) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InlineManifests.Merge(m, src)
+}
+func (m *InlineManifests) XXX_Size() int {
+	return xxx_messageInfo_InlineManifests.Size(m)
+}
+func (m *InlineManifests) XXX_DiscardUnknown() {
+	xxx_messageInfo_InlineManifests.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InlineManifests proto.InternalMessageInfo
+
+func (m *InlineManifests) GetYaml() string {
+	if m != nil {
+		return m.Yaml
+	}
+	return ""
+}
+
+// Location of a directory on the local filesystem
+type LocalLocation struct {
+	// Relative paths are resolved against the directory of the spec.yaml that references them.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// If true, the manifests in a manifests directory are rendered as go templates before they are applied.
+	// Ignored for helm charts.
+	Template             bool     `protobuf:"varint,2,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LocalLocation) Reset()         { *m = LocalLocation{} }
+func (m *LocalLocation) String() string { return proto.CompactTextString(m) }
+func (*LocalLocation) ProtoMessage()    {}
+func (*LocalLocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1ad3a89626d72ea, []int{25}
+}
+func (m *LocalLocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LocalLocation.Unmarshal(m, b)
+}
+func (m *LocalLocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LocalLocation.Marshal(b, m, deterministic)
+}
+func (m *LocalLocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocalLocation.Merge(m, src)
+}
+func (m *LocalLocation) XXX_Size() int {
+	return xxx_messageInfo_LocalLocation.Size(m)
+}
+func (m *LocalLocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocalLocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LocalLocation proto.InternalMessageInfo
+
+func (m *LocalLocation) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *LocalLocation) GetTemplate() bool {
+	if m != nil {
+		return m.Template
+	}
+	return false
+}
+
 type AllowedVersions struct {
 	MinVersion           string   `protobuf:"bytes,2,opt,name=min_version,json=minVersion,proto3" json:"min_version,omitempty"`
 	MaxVersion           string   `protobuf:"bytes,3,opt,name=max_version,json=maxVersion,proto3" json:"max_version,omitempty"`
@@ -1615,7 +2344,7 @@ func (m *AllowedVersions) Reset()         { *m = AllowedVersions{} }
 func (m *AllowedVersions) String() string { return proto.CompactTextString(m) }
 func (*AllowedVersions) ProtoMessage()    {}
 func (*AllowedVersions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d1ad3a89626d72ea, []int{18}
+	return fileDescriptor_d1ad3a89626d72ea, []int{26}
 }
 func (m *AllowedVersions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AllowedVersions.Unmarshal(m, b)
@@ -1662,6 +2391,9 @@ func init() {
 	proto.RegisterType((*Flavor)(nil), "hub.solo.io.Flavor")
 	proto.RegisterType((*Layer)(nil), "hub.solo.io.Layer")
 	proto.RegisterType((*LayerOption)(nil), "hub.solo.io.LayerOption")
+	proto.RegisterType((*ResourcePatch)(nil), "hub.solo.io.ResourcePatch")
+	proto.RegisterType((*ResourceSelector)(nil), "hub.solo.io.ResourceSelector")
+	proto.RegisterType((*KustomizeOverlay)(nil), "hub.solo.io.KustomizeOverlay")
 	proto.RegisterType((*ResourceDependency)(nil), "hub.solo.io.ResourceDependency")
 	proto.RegisterType((*ResourceDependency_Secret)(nil), "hub.solo.io.ResourceDependency.Secret")
 	proto.RegisterType((*Parameter)(nil), "hub.solo.io.Parameter")
@@ -1673,124 +2405,164 @@ func init() {
 	proto.RegisterType((*RequirementSet)(nil), "hub.solo.io.RequirementSet")
 	proto.RegisterType((*MeshRequirement)(nil), "hub.solo.io.MeshRequirement")
 	proto.RegisterType((*GithubRepositoryLocation)(nil), "hub.solo.io.GithubRepositoryLocation")
+	proto.RegisterType((*GitRepositoryLocation)(nil), "hub.solo.io.GitRepositoryLocation")
 	proto.RegisterType((*TgzLocation)(nil), "hub.solo.io.TgzLocation")
+	proto.RegisterType((*HelmRepositoryLocation)(nil), "hub.solo.io.HelmRepositoryLocation")
+	proto.RegisterType((*OciChartLocation)(nil), "hub.solo.io.OciChartLocation")
+	proto.RegisterType((*InlineManifests)(nil), "hub.solo.io.InlineManifests")
+	proto.RegisterType((*LocalLocation)(nil), "hub.solo.io.LocalLocation")
 	proto.RegisterType((*AllowedVersions)(nil), "hub.solo.io.AllowedVersions")
 }
 
 func init() { proto.RegisterFile("api/v1/registry.proto", fileDescriptor_d1ad3a89626d72ea) }
 
 var fileDescriptor_d1ad3a89626d72ea = []byte{
-	// 1745 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0x4f, 0x6f, 0x23, 0xb7,
-	0x15, 0xd7, 0xe8, 0xbf, 0x9e, 0x64, 0x6b, 0x4c, 0x7b, 0x17, 0xb3, 0x4e, 0xb2, 0x76, 0x14, 0x6c,
-	0xe1, 0xec, 0x62, 0xe5, 0xac, 0x9b, 0xb4, 0x49, 0x81, 0x14, 0x90, 0xbd, 0x5a, 0xdb, 0xa9, 0x6d,
-	0x19, 0x94, 0x92, 0x36, 0xbd, 0x0c, 0x28, 0x89, 0x92, 0x88, 0x1d, 0xcd, 0x4c, 0x49, 0xca, 0x5d,
-	0xe5, 0xd2, 0x53, 0xaf, 0x45, 0xfb, 0x21, 0x5a, 0xe4, 0xd0, 0x2f, 0xd1, 0x53, 0x3f, 0x46, 0x81,
-	0x5e, 0xfa, 0x0d, 0x7a, 0x2e, 0x48, 0xce, 0x8c, 0x66, 0x64, 0x6f, 0xe2, 0x16, 0xe9, 0x45, 0x20,
-	0xdf, 0xfb, 0xbd, 0xc7, 0xc7, 0xf7, 0x7e, 0xe4, 0xe3, 0x08, 0x1e, 0x90, 0x90, 0x1d, 0xde, 0xbc,
-	0x38, 0xe4, 0x74, 0xca, 0x84, 0xe4, 0xcb, 0x76, 0xc8, 0x03, 0x19, 0xa0, 0xfa, 0x6c, 0x31, 0x6c,
-	0x8b, 0xc0, 0x0b, 0xda, 0x2c, 0xd8, 0xdd, 0x99, 0x06, 0xd3, 0x40, 0xcb, 0x0f, 0xd5, 0xc8, 0x40,
-	0x76, 0xf7, 0xa6, 0x41, 0x30, 0xf5, 0xe8, 0xa1, 0x9e, 0x0d, 0x17, 0x93, 0x43, 0xc9, 0xe6, 0x54,
-	0x48, 0x32, 0x0f, 0x23, 0xc0, 0x23, 0x65, 0xff, 0xfc, 0x35, 0x93, 0x87, 0xc9, 0x1a, 0x13, 0xa3,
-	0x6a, 0xfd, 0xad, 0x08, 0xcd, 0x4e, 0x18, 0x7a, 0x6c, 0x44, 0x24, 0x0b, 0xfc, 0x7e, 0x48, 0x47,
-	0xe8, 0x23, 0x28, 0xca, 0x65, 0x48, 0x1d, 0x6b, 0xdf, 0x3a, 0xd8, 0x3c, 0x7a, 0xb7, 0x9d, 0x8a,
-	0xa0, 0x9d, 0xc2, 0x0e, 0x96, 0x21, 0xc5, 0x1a, 0x89, 0x10, 0x14, 0x7d, 0x32, 0xa7, 0x4e, 0x7e,
-	0xdf, 0x3a, 0xa8, 0x61, 0x3d, 0x46, 0x8f, 0xa0, 0xea, 0x05, 0xd3, 0xc0, 0x5d, 0x70, 0xcf, 0x29,
-	0x68, 0x79, 0x45, 0xcd, 0xbf, 0xe4, 0x1e, 0x7a, 0x06, 0x5b, 0x62, 0x16, 0x70, 0xe9, 0x8e, 0xa9,
-	0x18, 0x71, 0x16, 0x2a, 0x6f, 0x4e, 0x51, 0x63, 0x6c, 0xad, 0x78, 0xb9, 0x92, 0xa3, 0x0f, 0xc1,
-	0xf6, 0x02, 0x7f, 0x9a, 0xc1, 0x96, 0x34, 0xb6, 0xa9, 0xe4, 0x69, 0xe8, 0x33, 0xd8, 0x1a, 0x07,
-	0xa3, 0xc5, 0x9c, 0xfa, 0x52, 0x47, 0xa8, 0xd7, 0x2e, 0x1b, 0xbf, 0x19, 0x85, 0x0a, 0xe2, 0x09,
-	0x6c, 0x72, 0x1a, 0x06, 0x82, 0xc9, 0x80, 0x2f, 0x35, 0xb2, 0xa2, 0x91, 0x1b, 0x2b, 0xa9, 0x82,
-	0x1d, 0xc2, 0x36, 0x59, 0xed, 0xd9, 0x1d, 0x71, 0x4a, 0x64, 0xc0, 0x9d, 0xaa, 0xc6, 0xa2, 0x94,
-	0xea, 0xc4, 0x68, 0xd0, 0x0b, 0xd8, 0x49, 0x1b, 0x84, 0x3c, 0xb8, 0x61, 0x63, 0xca, 0x9d, 0x9a,
-	0xb6, 0x48, 0x3b, 0xbb, 0x8e, 0x54, 0xe8, 0x13, 0x78, 0x98, 0x36, 0x99, 0x13, 0xe6, 0x4b, 0xc2,
-	0x7c, 0xca, 0x1d, 0xd0, 0x46, 0x0f, 0x52, 0xda, 0xcb, 0x44, 0x89, 0x4e, 0xa0, 0x31, 0x26, 0x92,
-	0x9a, 0x98, 0xe8, 0xd8, 0xa9, 0xef, 0x5b, 0x07, 0xf5, 0xa3, 0xdd, 0xb6, 0xa1, 0x43, 0x3b, 0xa6,
-	0x43, 0x7b, 0x10, 0xd3, 0xe1, 0xb8, 0xf8, 0xc7, 0x7f, 0xec, 0x59, 0xb8, 0xae, 0xac, 0x4e, 0x8c,
-	0x11, 0xea, 0x40, 0xf5, 0x86, 0x72, 0xc1, 0x02, 0x5f, 0x38, 0x8d, 0xfd, 0xc2, 0x41, 0xfd, 0xe8,
-	0x49, 0xa6, 0xe0, 0x5f, 0x19, 0x25, 0x1d, 0xaf, 0xb1, 0x04, 0x27, 0x66, 0xad, 0x57, 0x60, 0xaf,
-	0x29, 0x05, 0x3a, 0x82, 0x92, 0x50, 0x03, 0xc7, 0xd2, 0x3e, 0xdf, 0x4a, 0x22, 0xed, 0xca, 0x40,
-	0x5b, 0x7f, 0x29, 0x83, 0xf3, 0xb6, 0xe5, 0x90, 0x03, 0x95, 0x68, 0x41, 0xcd, 0xcb, 0x1a, 0x8e,
-	0xa7, 0xe8, 0x14, 0x36, 0x75, 0x1a, 0xc2, 0xc5, 0xd0, 0x63, 0x62, 0x46, 0xc7, 0x9a, 0x86, 0xf7,
-	0x49, 0xc4, 0x86, 0xb2, 0xbb, 0x8e, 0xcd, 0xd0, 0x17, 0xd0, 0x98, 0x32, 0x39, 0x5b, 0x0c, 0xdd,
-	0xd1, 0x8c, 0x70, 0xe9, 0x6c, 0x68, 0x37, 0xd9, 0x74, 0x9c, 0x6a, 0x00, 0x4e, 0x28, 0x72, 0x11,
-	0x98, 0x18, 0xcf, 0x72, 0xb8, 0x6e, 0x8c, 0x4f, 0x94, 0x2d, 0xfa, 0x1c, 0x1a, 0x33, 0xea, 0xcd,
-	0x5d, 0xc2, 0x47, 0x33, 0x76, 0x43, 0x9d, 0x4d, 0xed, 0xcb, 0xc9, 0xf8, 0x1a, 0x4c, 0xbf, 0x49,
-	0x9b, 0x2b, 0x7c, 0xc7, 0xc0, 0xd1, 0x29, 0x6c, 0xcd, 0x89, 0xcf, 0x26, 0x54, 0x48, 0x91, 0xf8,
-	0x68, 0x7e, 0xaf, 0x0f, 0x3b, 0x31, 0x8a, 0x1d, 0xf5, 0x00, 0x31, 0x5f, 0x48, 0xe2, 0x79, 0x86,
-	0x5b, 0x42, 0xd2, 0x50, 0x38, 0xb6, 0xf6, 0xf4, 0x38, 0xe3, 0xe9, 0x3c, 0x05, 0xeb, 0x2b, 0xd4,
-	0x59, 0x0e, 0x6f, 0xb1, 0x75, 0x21, 0xda, 0x83, 0xfa, 0x0d, 0xf1, 0x16, 0x54, 0xb8, 0x4b, 0x32,
-	0xf7, 0x9c, 0xc7, 0xba, 0x16, 0x60, 0x44, 0x5f, 0x93, 0xb9, 0x87, 0x86, 0xd0, 0xe4, 0xf4, 0x37,
-	0x0b, 0xc6, 0xe9, 0xd8, 0xf5, 0xc8, 0x90, 0x7a, 0xc2, 0xd9, 0xd3, 0x1c, 0xf8, 0xec, 0x5e, 0xbc,
-	0x6a, 0xe3, 0xc8, 0xf8, 0x42, 0xdb, 0x76, 0x7d, 0xc9, 0x97, 0x78, 0x93, 0x67, 0x84, 0xe8, 0x39,
-	0x54, 0x26, 0x1e, 0xb9, 0x09, 0xb8, 0x70, 0x0e, 0xb4, 0xef, 0xed, 0x8c, 0xef, 0x57, 0x5a, 0x87,
-	0x63, 0x0c, 0xfa, 0x39, 0xbc, 0xc3, 0xa9, 0xe2, 0x98, 0x74, 0xe3, 0x04, 0xb9, 0xea, 0x8e, 0x12,
-	0x21, 0x19, 0x51, 0xe1, 0x7c, 0xb8, 0x6f, 0x1d, 0x54, 0xf1, 0xa3, 0x08, 0x72, 0x19, 0x21, 0xae,
-	0x12, 0x00, 0xfa, 0x09, 0x40, 0x48, 0x38, 0x99, 0x53, 0x49, 0xb9, 0x70, 0x9e, 0xea, 0x15, 0x1f,
-	0x66, 0x56, 0xbc, 0x8e, 0xd5, 0x38, 0x85, 0xdc, 0xed, 0xc0, 0xf6, 0x1d, 0xbb, 0x41, 0x36, 0x14,
-	0x5e, 0xd3, 0x65, 0x44, 0x63, 0x35, 0x44, 0x3b, 0x50, 0xd2, 0x19, 0x8c, 0x2e, 0x50, 0x33, 0xf9,
-	0x59, 0xfe, 0x53, 0xeb, 0x78, 0x1b, 0xb6, 0xb2, 0xf5, 0x0b, 0xe9, 0xa8, 0xf5, 0xf7, 0x3c, 0x6c,
-	0xdd, 0x2a, 0x17, 0xfa, 0x0c, 0x4a, 0xa6, 0xba, 0xe6, 0xc8, 0x7d, 0xf0, 0xdd, 0xd5, 0x6d, 0xab,
-	0x5f, 0x6c, 0x2c, 0x76, 0xff, 0x6d, 0x41, 0x51, 0xcd, 0x93, 0x8b, 0xbc, 0x98, 0xba, 0xc8, 0xd7,
-	0x8f, 0x85, 0xf5, 0x03, 0x1e, 0x8b, 0xfc, 0x0f, 0x70, 0x2c, 0x0a, 0xff, 0xfd, 0xb1, 0x38, 0x2e,
-	0x43, 0x51, 0xed, 0xbc, 0xf5, 0xfb, 0x3c, 0x94, 0x0d, 0x5b, 0x92, 0xad, 0x5b, 0xa9, 0xad, 0xef,
-	0x43, 0x3d, 0xdd, 0x76, 0x4c, 0x75, 0xd2, 0x22, 0xd4, 0x85, 0x9d, 0xd1, 0x42, 0xc8, 0x60, 0xce,
-	0xbe, 0x31, 0x05, 0xf2, 0xc8, 0x52, 0x91, 0xa4, 0xa0, 0x6b, 0x80, 0x32, 0x41, 0x5d, 0x28, 0x15,
-	0xde, 0xce, 0xe0, 0xb5, 0x4c, 0xa0, 0x57, 0x60, 0x47, 0x14, 0x57, 0x3d, 0xca, 0x15, 0x54, 0x0a,
-	0xa7, 0xa8, 0x5d, 0xbc, 0x93, 0x71, 0x81, 0x57, 0xa0, 0x3e, 0x95, 0xb8, 0xc9, 0x33, 0xf3, 0x75,
-	0xa6, 0x96, 0xee, 0xcb, 0xd4, 0xd6, 0x5f, 0x2d, 0x28, 0xe9, 0x50, 0xd0, 0x26, 0xe4, 0xd9, 0x38,
-	0x4a, 0x42, 0x9e, 0x8d, 0xd1, 0xfb, 0xd0, 0x18, 0x33, 0x11, 0x7a, 0x64, 0xe9, 0xa6, 0x5a, 0x7c,
-	0x3d, 0x92, 0x5d, 0xdd, 0x91, 0xa5, 0xc2, 0xed, 0x2c, 0xed, 0x42, 0x35, 0xd0, 0x23, 0xe2, 0x69,
-	0x6a, 0x55, 0x71, 0x32, 0x47, 0x47, 0x50, 0x31, 0xe3, 0x38, 0x5e, 0xe7, 0x76, 0xd2, 0x7a, 0x1a,
-	0x80, 0x63, 0x60, 0xeb, 0x4f, 0x79, 0xa8, 0xa7, 0x14, 0xff, 0x9f, 0xa0, 0xf7, 0x40, 0x73, 0xcf,
-	0x35, 0x77, 0x5b, 0xf4, 0xe6, 0x00, 0x25, 0xfa, 0x4a, 0x4b, 0xd6, 0x92, 0x5d, 0xbe, 0x6f, 0xb2,
-	0xd1, 0x00, 0x1e, 0x70, 0x2a, 0x82, 0x05, 0x1f, 0x51, 0x77, 0x4c, 0x43, 0xea, 0x8f, 0xa9, 0x3f,
-	0x62, 0x54, 0x38, 0x15, 0xed, 0x62, 0x6f, 0xad, 0xe2, 0x06, 0xf9, 0x32, 0x06, 0x2e, 0xf1, 0x0e,
-	0x5f, 0x97, 0x31, 0x2a, 0x5a, 0x7f, 0xb6, 0x00, 0xdd, 0x06, 0xa3, 0x2f, 0x61, 0x4b, 0xd0, 0x11,
-	0xa7, 0x72, 0xb5, 0xd4, 0x32, 0x3a, 0xc2, 0x3f, 0xfa, 0x9e, 0x85, 0xda, 0x7d, 0x6d, 0xa8, 0x0e,
-	0x90, 0x71, 0xb1, 0x52, 0xed, 0x7e, 0x04, 0x65, 0xa3, 0xbd, 0xf3, 0xdc, 0x20, 0x28, 0xbe, 0xa6,
-	0x4b, 0xe1, 0xe4, 0xf7, 0x0b, 0x4a, 0xa6, 0xc6, 0xea, 0xc8, 0xa9, 0xb7, 0x62, 0xeb, 0x5f, 0x16,
-	0xd4, 0x92, 0xbc, 0xfc, 0x8f, 0xa7, 0xae, 0x1d, 0xbd, 0x50, 0x0b, 0xfa, 0x85, 0xba, 0x7b, 0x77,
-	0xce, 0x53, 0xef, 0xd3, 0x4f, 0xa0, 0x32, 0xa6, 0x13, 0xb2, 0xf0, 0xa4, 0xa6, 0xdf, 0xfa, 0xa9,
-	0x4a, 0x4c, 0x74, 0x61, 0x71, 0x8c, 0x55, 0xb4, 0x8d, 0x1b, 0x8f, 0x2e, 0x7f, 0x15, 0x27, 0xf3,
-	0x5b, 0x14, 0x2b, 0xdf, 0xa2, 0x58, 0xeb, 0xdb, 0x3c, 0x6c, 0x66, 0x5d, 0xa3, 0x0f, 0xa0, 0x21,
-	0x24, 0x67, 0xfe, 0xd4, 0xb0, 0xca, 0x6c, 0x5b, 0xdd, 0x72, 0x46, 0x6a, 0x40, 0xef, 0x41, 0x8d,
-	0xf9, 0xd2, 0x5d, 0x75, 0x84, 0xc2, 0x59, 0x0e, 0x57, 0x99, 0x2f, 0x8d, 0xfa, 0x7d, 0xa8, 0x4f,
-	0xbc, 0x80, 0xc4, 0x00, 0x95, 0x03, 0xeb, 0x2c, 0x87, 0x41, 0x0b, 0x0d, 0xe4, 0x09, 0x6c, 0x0c,
-	0x83, 0xc0, 0xa3, 0xc4, 0x8f, 0x40, 0xfa, 0xd0, 0x9d, 0xe5, 0x70, 0x23, 0x12, 0x1b, 0x58, 0x07,
-	0x40, 0xbf, 0x9c, 0x0c, 0xa6, 0x74, 0xbf, 0x57, 0xd3, 0x59, 0x0e, 0xd7, 0x94, 0x95, 0x71, 0xf1,
-	0x39, 0x34, 0x22, 0x7a, 0x19, 0x27, 0xe5, 0x3b, 0x2e, 0x63, 0x43, 0x14, 0x8d, 0xd7, 0x5b, 0x5d,
-	0x4d, 0x13, 0x52, 0x7c, 0x01, 0x35, 0x83, 0xc2, 0x74, 0x82, 0x9e, 0x41, 0x81, 0xd3, 0x49, 0x44,
-	0xd2, 0x47, 0xed, 0x51, 0xc0, 0xe9, 0x2d, 0x96, 0x62, 0x3a, 0xc1, 0x0a, 0x15, 0x37, 0xd3, 0x7c,
-	0xd2, 0x4c, 0x5b, 0x7f, 0xb0, 0xa0, 0x9e, 0x5a, 0x12, 0xfd, 0x14, 0x20, 0x0a, 0x71, 0xe5, 0xf5,
-	0xe1, 0x1d, 0x01, 0x62, 0x3a, 0x51, 0x7b, 0x13, 0x49, 0x1c, 0xef, 0x41, 0x6d, 0xc2, 0x3c, 0xea,
-	0x86, 0x44, 0xce, 0xcc, 0x02, 0xaa, 0x0e, 0x4a, 0x74, 0x4d, 0xe4, 0x0c, 0xed, 0x01, 0x84, 0x1e,
-	0x61, 0xbe, 0x2b, 0xe9, 0x1b, 0x69, 0x2e, 0x10, 0x65, 0xaf, 0x65, 0x03, 0xfa, 0x46, 0x26, 0x9b,
-	0x9b, 0xc2, 0xb6, 0xe9, 0x31, 0x27, 0xc1, 0x3c, 0x24, 0x92, 0x0d, 0x99, 0xc7, 0xe4, 0x12, 0x5d,
-	0x83, 0x3d, 0x8a, 0x04, 0x7a, 0x11, 0xc6, 0xe3, 0xd6, 0x9d, 0xed, 0xad, 0x27, 0x09, 0xc8, 0x78,
-	0xb9, 0xa4, 0x62, 0x76, 0x4d, 0x18, 0xc7, 0xcd, 0x95, 0xb9, 0x9a, 0x8b, 0xd6, 0x0d, 0x38, 0x6f,
-	0x03, 0xa3, 0x67, 0x50, 0x36, 0xcf, 0xa1, 0x28, 0x03, 0x77, 0xbe, 0x98, 0x22, 0x08, 0x7a, 0x0e,
-	0xc5, 0x39, 0x15, 0xb3, 0xa8, 0x3d, 0x7f, 0x47, 0x09, 0x34, 0xac, 0xf5, 0x35, 0x6c, 0x66, 0x1b,
-	0x13, 0x3a, 0x05, 0x5b, 0x69, 0xdc, 0x54, 0x7f, 0x8a, 0xd6, 0xcd, 0x7e, 0x09, 0xa8, 0xf0, 0x52,
-	0xa6, 0xb8, 0x39, 0xcf, 0x0a, 0x5a, 0xbf, 0x83, 0xe6, 0x1a, 0x06, 0x1d, 0x41, 0x4d, 0xfb, 0x4e,
-	0x7d, 0xa3, 0x3e, 0xb8, 0xe5, 0x54, 0x1f, 0xfe, 0xea, 0x3c, 0x1a, 0xa1, 0x4f, 0x53, 0x5f, 0x39,
-	0xf9, 0x3b, 0xe2, 0xe8, 0x78, 0x5e, 0xf0, 0x5b, 0x3a, 0x8e, 0x1e, 0xa5, 0x22, 0xf5, 0x71, 0x13,
-	0x82, 0xf3, 0xb6, 0xc7, 0x8d, 0xe2, 0x5e, 0xc0, 0xa7, 0xf1, 0x43, 0x2e, 0xe0, 0x53, 0x75, 0x9d,
-	0xa9, 0xcf, 0xc7, 0xf8, 0x43, 0x58, 0x8d, 0x15, 0x4a, 0x11, 0xcf, 0x74, 0x18, 0xcd, 0xd9, 0x77,
-	0xa1, 0x36, 0x66, 0x9c, 0x8e, 0x94, 0xb3, 0xe8, 0xa9, 0xb5, 0x12, 0xb4, 0xf6, 0xa0, 0x9e, 0x7a,
-	0xbe, 0x28, 0xf3, 0x05, 0x67, 0xf1, 0x22, 0x0b, 0xce, 0x5a, 0x7d, 0x68, 0xae, 0xc5, 0xab, 0x7a,
-	0xd5, 0x9c, 0xf9, 0x6e, 0xfc, 0x85, 0x64, 0x96, 0x87, 0x39, 0xf3, 0x23, 0x84, 0x06, 0x90, 0x37,
-	0x09, 0xa0, 0x10, 0x01, 0xc8, 0x9b, 0x08, 0xf0, 0xb4, 0x07, 0x1b, 0x99, 0x9b, 0x13, 0x01, 0x94,
-	0xfb, 0x03, 0x7c, 0x7e, 0x75, 0x6a, 0xe7, 0x50, 0x0d, 0x4a, 0xaf, 0x2e, 0x7a, 0x9d, 0x81, 0x6d,
-	0xa1, 0x2a, 0x14, 0x8f, 0x7b, 0xbd, 0x0b, 0x3b, 0x8f, 0x2a, 0x50, 0x38, 0xbf, 0x1a, 0xd8, 0x05,
-	0x25, 0x7a, 0xd9, 0x19, 0x74, 0xed, 0xa2, 0xb6, 0xe9, 0x9e, 0xe0, 0xee, 0xc0, 0x2e, 0x3d, 0xfd,
-	0x38, 0xf3, 0xc7, 0x82, 0x76, 0xb9, 0x01, 0xb5, 0xee, 0xaf, 0x06, 0xdd, 0xab, 0xfe, 0x79, 0xef,
-	0xca, 0xce, 0x69, 0xbb, 0xee, 0x65, 0xcf, 0x38, 0xbd, 0xec, 0xf6, 0xcf, 0xec, 0xfc, 0xd3, 0x8f,
-	0xa1, 0x1a, 0x97, 0x4f, 0xad, 0x7a, 0xde, 0x1f, 0x9c, 0xf7, 0xec, 0x1c, 0xaa, 0x43, 0xe5, 0xe2,
-	0xfc, 0xea, 0x17, 0x5d, 0xfc, 0xd2, 0xb6, 0x90, 0x0d, 0x8d, 0xce, 0x2f, 0xfb, 0x6e, 0xe7, 0xfa,
-	0xda, 0x35, 0x56, 0xc7, 0xd5, 0x6f, 0xff, 0xf9, 0xd8, 0xfa, 0x75, 0xfe, 0xe6, 0xc5, 0xb0, 0xac,
-	0xaf, 0xad, 0x1f, 0xff, 0x27, 0x00, 0x00, 0xff, 0xff, 0x81, 0x93, 0x33, 0x9f, 0x4e, 0x11, 0x00,
-	0x00,
+	// 2312 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x5b, 0x6f, 0x1b, 0xc7,
+	0xf5, 0xd7, 0xf2, 0xce, 0x43, 0x49, 0x5c, 0x8d, 0x2e, 0x58, 0x2b, 0xb1, 0xa5, 0xac, 0xe1, 0x3f,
+	0x14, 0x1b, 0xa6, 0x62, 0xc5, 0xc9, 0x3f, 0xae, 0xeb, 0x06, 0x92, 0x4c, 0x5b, 0x4a, 0x24, 0x51,
+	0x58, 0xd2, 0x69, 0xdd, 0x17, 0x62, 0xb5, 0x1c, 0x92, 0x53, 0xef, 0xad, 0xb3, 0x43, 0x45, 0xcc,
+	0x4b, 0x81, 0xa2, 0x7d, 0x2d, 0x8a, 0x3e, 0xf5, 0x0b, 0x14, 0x08, 0x8a, 0x7e, 0x89, 0xbe, 0xf5,
+	0x5b, 0x14, 0xe8, 0x5b, 0xbf, 0x45, 0x31, 0x97, 0x5d, 0xee, 0x92, 0x54, 0xac, 0x3a, 0xee, 0x8b,
+	0x30, 0x73, 0xce, 0xef, 0x9c, 0x39, 0x73, 0xf6, 0x5c, 0xe6, 0x50, 0xb0, 0x6e, 0x87, 0x64, 0xf7,
+	0xf2, 0xd1, 0x2e, 0xc5, 0x03, 0x12, 0x31, 0x3a, 0x6e, 0x84, 0x34, 0x60, 0x01, 0xaa, 0x0d, 0x47,
+	0x17, 0x8d, 0x28, 0x70, 0x83, 0x06, 0x09, 0x36, 0xd7, 0x06, 0xc1, 0x20, 0x10, 0xf4, 0x5d, 0xbe,
+	0x92, 0x90, 0xcd, 0xad, 0x41, 0x10, 0x0c, 0x5c, 0xbc, 0x2b, 0x76, 0x17, 0xa3, 0xfe, 0x2e, 0x23,
+	0x1e, 0x8e, 0x98, 0xed, 0x85, 0x0a, 0x70, 0x67, 0x1a, 0xf0, 0x2d, 0xb5, 0xc3, 0x10, 0xd3, 0x48,
+	0xf1, 0x6f, 0x71, 0xfd, 0x0f, 0xdf, 0x10, 0xb6, 0x9b, 0xd8, 0xd0, 0x97, 0x2c, 0xf3, 0xef, 0x05,
+	0xa8, 0xef, 0x87, 0xa1, 0x4b, 0x1c, 0x9b, 0x91, 0xc0, 0x6f, 0x87, 0xd8, 0x41, 0x9f, 0x40, 0x81,
+	0x8d, 0x43, 0x6c, 0x68, 0xdb, 0xda, 0xce, 0xf2, 0xde, 0x87, 0x8d, 0x94, 0x85, 0x8d, 0x14, 0xb6,
+	0x33, 0x0e, 0xb1, 0x25, 0x90, 0x08, 0x41, 0xc1, 0xb7, 0x3d, 0x6c, 0xe4, 0xb6, 0xb5, 0x9d, 0xaa,
+	0x25, 0xd6, 0xe8, 0x16, 0x54, 0xdc, 0x60, 0x10, 0x74, 0x47, 0xd4, 0x35, 0xf2, 0x82, 0x5e, 0xe6,
+	0xfb, 0x57, 0xd4, 0x45, 0x0f, 0x60, 0x25, 0x1a, 0x06, 0x94, 0x75, 0x7b, 0x38, 0x72, 0x28, 0x09,
+	0xb9, 0x36, 0xa3, 0x20, 0x30, 0xba, 0x60, 0x3c, 0x9f, 0xd0, 0xd1, 0xc7, 0xa0, 0xbb, 0x81, 0x3f,
+	0xc8, 0x60, 0x8b, 0x02, 0x5b, 0xe7, 0xf4, 0x34, 0xf4, 0x01, 0xac, 0xf4, 0x02, 0x67, 0xe4, 0x61,
+	0x9f, 0x09, 0x0b, 0xc5, 0xd9, 0x25, 0xa9, 0x37, 0xc3, 0xe0, 0x46, 0xdc, 0x83, 0x65, 0x8a, 0xc3,
+	0x20, 0x22, 0x2c, 0xa0, 0x63, 0x81, 0x2c, 0x0b, 0xe4, 0xd2, 0x84, 0xca, 0x61, 0xbb, 0xb0, 0x6a,
+	0x4f, 0xee, 0xdc, 0x75, 0x28, 0xb6, 0x59, 0x40, 0x8d, 0x8a, 0xc0, 0xa2, 0x14, 0xeb, 0x50, 0x72,
+	0xd0, 0x23, 0x58, 0x4b, 0x0b, 0x84, 0x34, 0xb8, 0x24, 0x3d, 0x4c, 0x8d, 0xaa, 0x90, 0x48, 0x2b,
+	0x3b, 0x57, 0x2c, 0xf4, 0x19, 0x6c, 0xa4, 0x45, 0x3c, 0x9b, 0xf8, 0xcc, 0x26, 0x3e, 0xa6, 0x06,
+	0x08, 0xa1, 0xf5, 0x14, 0xf7, 0x34, 0x61, 0xa2, 0x43, 0x58, 0xec, 0xd9, 0x0c, 0x4b, 0x9b, 0x70,
+	0xcf, 0xa8, 0x6d, 0x6b, 0x3b, 0xb5, 0xbd, 0xcd, 0x86, 0x8c, 0x86, 0x46, 0x1c, 0x0d, 0x8d, 0x4e,
+	0x1c, 0x2e, 0x07, 0x85, 0x3f, 0xfe, 0x73, 0x4b, 0xb3, 0x6a, 0x5c, 0xea, 0x50, 0x0a, 0xa1, 0x7d,
+	0xa8, 0x5c, 0x62, 0x1a, 0x91, 0xc0, 0x8f, 0x8c, 0xc5, 0xed, 0xfc, 0x4e, 0x6d, 0xef, 0x5e, 0xe6,
+	0x83, 0x7f, 0x23, 0x99, 0xb8, 0x37, 0x15, 0x25, 0x56, 0x22, 0x66, 0xbe, 0x00, 0x7d, 0x8a, 0x19,
+	0xa1, 0x3d, 0x28, 0x46, 0x7c, 0x61, 0x68, 0x42, 0xe7, 0xb5, 0x41, 0x24, 0x54, 0x49, 0xa8, 0xf9,
+	0x8f, 0x2a, 0x18, 0xd7, 0x1d, 0x87, 0x0c, 0x28, 0xab, 0x03, 0x45, 0x5c, 0x56, 0xad, 0x78, 0x8b,
+	0x5e, 0xc2, 0xb2, 0x70, 0x43, 0x38, 0xba, 0x70, 0x49, 0x34, 0xc4, 0x3d, 0x23, 0x77, 0x43, 0x47,
+	0x2c, 0x71, 0xb9, 0xf3, 0x58, 0x0c, 0x7d, 0x05, 0x8b, 0x03, 0xc2, 0x86, 0xa3, 0x8b, 0xae, 0x33,
+	0xb4, 0x29, 0x33, 0x96, 0xb6, 0xb5, 0x19, 0x77, 0xbc, 0x14, 0x00, 0x2b, 0x09, 0x91, 0x93, 0x40,
+	0xda, 0x78, 0xb4, 0x60, 0xd5, 0xa4, 0xf0, 0x21, 0x97, 0x45, 0xcf, 0x60, 0x71, 0x88, 0x5d, 0xaf,
+	0x6b, 0x53, 0x67, 0x48, 0x2e, 0xb1, 0xb1, 0x2c, 0x74, 0x19, 0x19, 0x5d, 0x9d, 0xc1, 0x77, 0x69,
+	0x71, 0x8e, 0xdf, 0x97, 0x70, 0xf4, 0x12, 0x56, 0x3c, 0xdb, 0x27, 0x7d, 0x1c, 0xb1, 0x28, 0xd1,
+	0x51, 0x7f, 0xab, 0x0e, 0x3d, 0x11, 0x8a, 0x15, 0xb5, 0x00, 0x11, 0x3f, 0x62, 0xb6, 0xeb, 0xca,
+	0xd8, 0x8a, 0x18, 0x0e, 0x23, 0x43, 0x17, 0x9a, 0xee, 0x64, 0x34, 0x1d, 0xa7, 0x60, 0x6d, 0x8e,
+	0x3a, 0x5a, 0xb0, 0x56, 0xc8, 0x34, 0x11, 0x3d, 0x83, 0x9a, 0x1b, 0x38, 0xb6, 0xab, 0x7c, 0xb4,
+	0xa2, 0x5c, 0x9d, 0xd6, 0xc4, 0x0d, 0x72, 0x53, 0x56, 0x81, 0x10, 0x90, 0x7e, 0x69, 0x42, 0x5d,
+	0x8a, 0x27, 0x96, 0x1a, 0xe8, 0x06, 0x2a, 0x96, 0x85, 0xd0, 0x69, 0x2c, 0x83, 0xce, 0xa0, 0x2e,
+	0xdc, 0x3b, 0xc9, 0x55, 0x63, 0x55, 0xa8, 0xb9, 0x9b, 0x51, 0x73, 0x84, 0x5d, 0x6f, 0xee, 0xb7,
+	0x5a, 0x1e, 0x66, 0x38, 0xe8, 0xa7, 0x50, 0x0d, 0x1c, 0xa2, 0xee, 0xb4, 0x26, 0x34, 0xdd, 0xce,
+	0x68, 0x6a, 0x39, 0x44, 0x5c, 0x20, 0xa5, 0xa3, 0x12, 0x28, 0x1a, 0xda, 0x87, 0xea, 0x80, 0x30,
+	0x25, 0xbd, 0x2e, 0xa4, 0xcd, 0xe9, 0xa8, 0x99, 0x6b, 0x46, 0x65, 0x40, 0x98, 0x54, 0x71, 0x0c,
+	0x3a, 0xf1, 0x5d, 0xe2, 0xe3, 0x94, 0x63, 0x36, 0xb6, 0xb5, 0x99, 0xd4, 0x39, 0x16, 0xa0, 0xc4,
+	0x11, 0x47, 0x0b, 0x56, 0x9d, 0x64, 0x49, 0x68, 0x0b, 0x6a, 0x97, 0xb6, 0x3b, 0xc2, 0x51, 0x77,
+	0x6c, 0x7b, 0xae, 0x71, 0x47, 0x64, 0x0b, 0x48, 0xd2, 0x6b, 0xdb, 0x73, 0xd1, 0x05, 0xd4, 0x29,
+	0xfe, 0xf5, 0x88, 0x50, 0xdc, 0xeb, 0xba, 0xf6, 0x05, 0x76, 0x23, 0x63, 0x4b, 0x64, 0xe9, 0x93,
+	0x1b, 0x65, 0x7e, 0xc3, 0x52, 0xc2, 0x27, 0x42, 0xb6, 0xe9, 0x33, 0x3a, 0xb6, 0x96, 0x69, 0x86,
+	0x88, 0x1e, 0x42, 0xb9, 0xef, 0xda, 0x97, 0x01, 0x8d, 0x8c, 0x1d, 0xa1, 0x7b, 0x35, 0xa3, 0xfb,
+	0x85, 0xe0, 0x59, 0x31, 0x06, 0xfd, 0x0c, 0x3e, 0xa0, 0x98, 0x57, 0x01, 0x96, 0xdc, 0xbf, 0xcb,
+	0xbb, 0x48, 0x14, 0xda, 0x0e, 0x8e, 0x8c, 0x8f, 0xb7, 0xb5, 0x9d, 0x8a, 0x75, 0x4b, 0x41, 0xe2,
+	0xab, 0x9e, 0x25, 0x00, 0xf4, 0x39, 0x40, 0x68, 0x53, 0xdb, 0xc3, 0x0c, 0xd3, 0xc8, 0xb8, 0x2f,
+	0x4e, 0xdc, 0xc8, 0x9c, 0x78, 0x1e, 0xb3, 0xad, 0x14, 0x72, 0x73, 0x1f, 0x56, 0xe7, 0xdc, 0x06,
+	0xe9, 0x90, 0x7f, 0x83, 0xc7, 0xaa, 0xd0, 0xf0, 0x25, 0x5a, 0x83, 0xa2, 0xf0, 0xa0, 0x6a, 0x71,
+	0x72, 0xf3, 0x93, 0xdc, 0x17, 0xda, 0xc1, 0x2a, 0xac, 0x64, 0x33, 0x2c, 0xc4, 0x8e, 0xf9, 0xef,
+	0x22, 0xac, 0xcc, 0x24, 0x14, 0x7a, 0x02, 0x45, 0x99, 0x7f, 0xb2, 0x28, 0xde, 0xfd, 0xe1, 0xfc,
+	0x6b, 0xf0, 0xbf, 0x96, 0x94, 0xd8, 0xfc, 0x73, 0x11, 0x0a, 0x7c, 0x9f, 0xb4, 0xda, 0x42, 0xaa,
+	0xd5, 0x4e, 0x17, 0x2e, 0xed, 0x3d, 0x16, 0xae, 0xdc, 0x7b, 0x28, 0x5c, 0xf9, 0x77, 0x28, 0x5c,
+	0x53, 0x75, 0xa6, 0xf8, 0xe3, 0xeb, 0x4c, 0xe9, 0xfd, 0xd4, 0x99, 0xf2, 0x7b, 0xab, 0x33, 0x95,
+	0x1f, 0x55, 0x67, 0xaa, 0xef, 0xad, 0xce, 0xc0, 0x3b, 0xd5, 0x99, 0x83, 0x12, 0x14, 0x78, 0x6c,
+	0x9a, 0xbf, 0xcf, 0x41, 0x49, 0xe6, 0x73, 0x12, 0x9c, 0x5a, 0x2a, 0x38, 0xb7, 0xa1, 0x96, 0x7e,
+	0xba, 0xc9, 0xfc, 0x49, 0x93, 0x50, 0x13, 0xd6, 0x9c, 0x51, 0xc4, 0x02, 0x8f, 0x7c, 0x27, 0x53,
+	0xc8, 0xb5, 0xc7, 0x3c, 0x8d, 0xf3, 0x22, 0x4b, 0x50, 0xf6, 0x83, 0x71, 0x96, 0xb5, 0x9a, 0xc1,
+	0x0b, 0x5a, 0x84, 0x5e, 0x80, 0xae, 0x8a, 0x10, 0x7f, 0xe7, 0x75, 0x23, 0xcc, 0x22, 0xa3, 0x20,
+	0x54, 0x7c, 0x90, 0x51, 0x61, 0x4d, 0x40, 0x6d, 0xcc, 0xac, 0x3a, 0xcd, 0xec, 0xa7, 0x6b, 0x49,
+	0xf1, 0xa6, 0xb5, 0xc4, 0xfc, 0x9b, 0x06, 0x45, 0x61, 0x0a, 0x5a, 0x86, 0x1c, 0xe9, 0x29, 0x27,
+	0xe4, 0x48, 0x0f, 0x7d, 0x04, 0x8b, 0x3d, 0x12, 0x85, 0xae, 0x3d, 0xee, 0xa6, 0x9e, 0xc9, 0x35,
+	0x45, 0x3b, 0x9b, 0xe3, 0xa5, 0xfc, 0xac, 0x97, 0x36, 0xa1, 0x12, 0x88, 0x95, 0xed, 0x8a, 0xe4,
+	0xaf, 0x58, 0xc9, 0x1e, 0xed, 0x41, 0x59, 0xae, 0x63, 0x7b, 0x8d, 0x59, 0xa7, 0xb5, 0x04, 0xc0,
+	0x8a, 0x81, 0xe6, 0xef, 0xf2, 0x50, 0x4b, 0x31, 0xfe, 0x37, 0x46, 0x6f, 0x81, 0xa8, 0x0e, 0x5d,
+	0xd9, 0x7d, 0xd4, 0xbb, 0x1d, 0x38, 0xe9, 0x1b, 0x41, 0x99, 0x72, 0x76, 0xe9, 0xa6, 0xce, 0x46,
+	0x1d, 0x58, 0xa7, 0x38, 0x0a, 0x46, 0xd4, 0xc1, 0xdd, 0x1e, 0x0e, 0xb1, 0xdf, 0xc3, 0xbe, 0x43,
+	0x70, 0x64, 0x94, 0x85, 0x8a, 0xad, 0xa9, 0x2f, 0x2e, 0x91, 0xcf, 0x63, 0xe0, 0xd8, 0x5a, 0xa3,
+	0xd3, 0x34, 0x82, 0x23, 0xf4, 0x14, 0xaa, 0x6f, 0x54, 0x64, 0xe1, 0xb9, 0xe9, 0xf9, 0x75, 0xcc,
+	0x6d, 0x5d, 0x62, 0xea, 0xda, 0x63, 0x6b, 0x82, 0x47, 0x8f, 0xa1, 0x1c, 0xda, 0xcc, 0x19, 0xe2,
+	0xc8, 0xa8, 0x6e, 0xe7, 0x67, 0x4a, 0x4d, 0x6c, 0xc4, 0x39, 0xc7, 0x58, 0x31, 0xd4, 0xfc, 0xab,
+	0x06, 0x4b, 0x19, 0x16, 0x7a, 0x02, 0x95, 0x08, 0xbb, 0xd8, 0xe1, 0x63, 0x86, 0x36, 0xc7, 0x86,
+	0x18, 0xdd, 0x56, 0x20, 0x2b, 0x81, 0xa3, 0x2d, 0x80, 0x5f, 0x45, 0x7c, 0xe8, 0xe0, 0x8a, 0xe4,
+	0x17, 0x3b, 0x5a, 0xb0, 0xaa, 0x9c, 0x26, 0x75, 0x3f, 0x86, 0xf5, 0x88, 0x51, 0x9b, 0xe1, 0x01,
+	0x71, 0xba, 0x1e, 0xa6, 0x03, 0xac, 0xb0, 0x79, 0x85, 0x5d, 0x4d, 0xd8, 0xa7, 0x9c, 0x2b, 0xa4,
+	0x0e, 0xca, 0x50, 0x14, 0x28, 0xd3, 0x07, 0x7d, 0xfa, 0x74, 0xde, 0x19, 0x07, 0x34, 0x18, 0x85,
+	0x2a, 0x74, 0xe4, 0x86, 0x57, 0x82, 0x37, 0xc4, 0xef, 0xc5, 0x13, 0x21, 0x5f, 0x27, 0xd5, 0x21,
+	0x9f, 0xaa, 0x0e, 0x1f, 0x42, 0x35, 0xe9, 0xf3, 0xaa, 0xa7, 0x4d, 0x08, 0xe6, 0x6f, 0x35, 0xd0,
+	0xa7, 0x5d, 0x8e, 0xbe, 0x84, 0x92, 0x6c, 0x58, 0xff, 0x6d, 0x9f, 0x53, 0x62, 0x3c, 0xb2, 0x03,
+	0xa9, 0x8b, 0x5f, 0x7e, 0x18, 0x47, 0xb6, 0xa2, 0x9d, 0xdb, 0x6c, 0x78, 0x00, 0x7c, 0x78, 0x95,
+	0x82, 0xe6, 0x5f, 0x34, 0x40, 0xb3, 0x11, 0x84, 0x5e, 0xc1, 0x4a, 0x84, 0x1d, 0x8a, 0xd9, 0x24,
+	0xfe, 0xc6, 0xca, 0xa2, 0xff, 0x7b, 0x4b, 0xf4, 0x35, 0xda, 0x42, 0x90, 0xf7, 0x3d, 0xa9, 0x62,
+	0xc2, 0xda, 0xfc, 0x04, 0x4a, 0x92, 0x3b, 0xb7, 0x98, 0x72, 0xb7, 0xe2, 0x71, 0x64, 0xe4, 0xb6,
+	0xf3, 0xc2, 0xad, 0x78, 0x2c, 0xea, 0x30, 0x1f, 0xc2, 0xcd, 0x3f, 0x15, 0xa0, 0x9a, 0x24, 0xcb,
+	0x3b, 0x96, 0xe2, 0x86, 0x1a, 0xfd, 0xf3, 0x62, 0xf4, 0xdf, 0x9c, 0x9f, 0x88, 0xa9, 0xc1, 0xff,
+	0x33, 0x28, 0xf7, 0x70, 0xdf, 0x1e, 0xb9, 0x4c, 0x7c, 0xbc, 0xe9, 0x52, 0x9b, 0x88, 0x88, 0x6c,
+	0xb7, 0x62, 0x2c, 0xaf, 0x65, 0xf1, 0x7b, 0x51, 0xd4, 0x84, 0x8a, 0x95, 0xec, 0x67, 0xea, 0x4e,
+	0x69, 0xb6, 0xee, 0xdc, 0x83, 0x65, 0xdb, 0x75, 0x83, 0x6f, 0x71, 0x2f, 0x2e, 0x2c, 0x65, 0xe1,
+	0x8f, 0x25, 0x45, 0x55, 0xb5, 0xc5, 0x10, 0x09, 0xc9, 0x30, 0xf5, 0xd5, 0xb8, 0x1e, 0x6f, 0xd1,
+	0xe7, 0x50, 0xf6, 0x88, 0x4f, 0xbc, 0x91, 0xa7, 0xda, 0xe8, 0x87, 0x33, 0xb3, 0xe2, 0xf3, 0x60,
+	0x74, 0xe1, 0x62, 0x65, 0xb7, 0x02, 0x0b, 0x39, 0xfb, 0x4a, 0xc8, 0xc1, 0x8d, 0xe4, 0x24, 0x18,
+	0x3d, 0x05, 0xf0, 0x88, 0xdf, 0x75, 0xb1, 0x3f, 0x60, 0x43, 0xa3, 0x76, 0x8d, 0xe8, 0xab, 0x63,
+	0x9f, 0x7d, 0xba, 0x27, 0x45, 0xab, 0x1e, 0xf1, 0x4f, 0x04, 0x5c, 0x08, 0xdb, 0x57, 0xb1, 0xf0,
+	0xe2, 0x8d, 0x84, 0xed, 0x2b, 0x29, 0x6c, 0x7e, 0x9f, 0x83, 0xe5, 0xec, 0x57, 0x40, 0x77, 0x61,
+	0x31, 0x62, 0x94, 0xf8, 0x03, 0xe9, 0x3c, 0x19, 0x21, 0xfc, 0x1d, 0x27, 0xa9, 0x12, 0x74, 0x1b,
+	0xaa, 0xc4, 0x67, 0xdd, 0xc9, 0x9b, 0x37, 0xcf, 0x9f, 0x11, 0xc4, 0x67, 0x92, 0xfd, 0x11, 0xd4,
+	0xfa, 0x6e, 0x60, 0xc7, 0x00, 0x1e, 0x2e, 0x1a, 0x7f, 0x81, 0x09, 0xa2, 0x84, 0xdc, 0x83, 0xa5,
+	0x8b, 0x20, 0x70, 0xb1, 0xed, 0x2b, 0x90, 0x68, 0x5a, 0x47, 0x0b, 0xd6, 0xa2, 0x22, 0x4b, 0xd8,
+	0x3e, 0x80, 0x98, 0xde, 0x25, 0xa6, 0x78, 0xb3, 0xc9, 0x9d, 0x17, 0x35, 0x2e, 0x25, 0x55, 0x3c,
+	0x83, 0x45, 0x95, 0x89, 0x52, 0x49, 0x69, 0xce, 0x73, 0x53, 0xe6, 0x94, 0xc0, 0x8b, 0xab, 0x4e,
+	0xb6, 0x49, 0xfe, 0x7c, 0x05, 0x55, 0x89, 0xb2, 0x70, 0x1f, 0x3d, 0x80, 0x3c, 0xc5, 0x7d, 0x95,
+	0xcf, 0xb7, 0x1a, 0x4e, 0x40, 0xf1, 0x4c, 0x42, 0x5b, 0xb8, 0x6f, 0x71, 0x54, 0x3c, 0x2e, 0xe4,
+	0x92, 0x71, 0xc1, 0xfc, 0x83, 0x06, 0xb5, 0xd4, 0x91, 0xe8, 0xff, 0x01, 0x94, 0x89, 0x13, 0xad,
+	0x1b, 0x73, 0x0c, 0xb4, 0x70, 0x9f, 0xdf, 0x2d, 0x4a, 0xec, 0xb8, 0x0d, 0xd5, 0x3e, 0x71, 0x71,
+	0xaa, 0x50, 0xf1, 0xef, 0xc0, 0x49, 0xbc, 0x4e, 0xf1, 0x82, 0x1f, 0xba, 0x36, 0xf1, 0xbb, 0x0c,
+	0x5f, 0xb1, 0xa4, 0x88, 0x57, 0x05, 0xad, 0x83, 0xaf, 0x58, 0x72, 0xb9, 0x01, 0xac, 0xca, 0x37,
+	0xda, 0x61, 0xe0, 0x85, 0x36, 0x23, 0x17, 0xc4, 0x25, 0x6c, 0x8c, 0xce, 0x41, 0x77, 0x14, 0x41,
+	0x1c, 0x42, 0x68, 0x3c, 0x9c, 0x64, 0xab, 0xea, 0x61, 0x02, 0x92, 0x5a, 0x4e, 0x71, 0x34, 0x3c,
+	0xb7, 0x09, 0xb5, 0xea, 0x13, 0x71, 0xbe, 0x8f, 0xcc, 0x4b, 0x30, 0xae, 0x03, 0xa3, 0x07, 0x50,
+	0x92, 0x03, 0x9f, 0xf2, 0xc0, 0xdc, 0x99, 0x50, 0x41, 0xd0, 0x43, 0x28, 0x78, 0x38, 0x1a, 0x1a,
+	0xb9, 0xb7, 0x7d, 0x02, 0x01, 0x33, 0x5f, 0xc3, 0x72, 0xf6, 0x61, 0x87, 0x5e, 0x82, 0xce, 0x39,
+	0xdd, 0xd4, 0xfb, 0xce, 0xd0, 0xe6, 0x3c, 0x75, 0xb9, 0x79, 0x29, 0x51, 0xab, 0xee, 0x65, 0x09,
+	0xe6, 0x6f, 0xa0, 0x3e, 0x85, 0x41, 0x7b, 0x50, 0x15, 0xba, 0x53, 0xbf, 0x93, 0xae, 0xcf, 0x28,
+	0x15, 0x75, 0xb2, 0xe2, 0xa9, 0x15, 0xfa, 0x22, 0xf5, 0x4b, 0x5b, 0x6e, 0x8e, 0x1d, 0xfb, 0xaa,
+	0x78, 0x29, 0x4c, 0xea, 0x07, 0xb6, 0x10, 0x8c, 0xeb, 0xda, 0x1a, 0x8f, 0xbd, 0x80, 0x0e, 0xe2,
+	0x51, 0x35, 0xa0, 0x03, 0x5e, 0xf9, 0xf9, 0xb8, 0x12, 0xb7, 0x5e, 0xbe, 0xe6, 0x28, 0x1e, 0x78,
+	0xb2, 0xf3, 0xf2, 0x25, 0x6f, 0xbc, 0x3d, 0x42, 0x45, 0x0b, 0x1f, 0xc7, 0x8d, 0x37, 0x21, 0x98,
+	0xaf, 0x61, 0x7d, 0xee, 0x2c, 0xc1, 0x15, 0xf1, 0x9f, 0x4a, 0xd5, 0x71, 0x23, 0xea, 0xc6, 0xaa,
+	0x73, 0xd7, 0xa8, 0xce, 0x4f, 0xab, 0x7e, 0x0a, 0xb5, 0xd4, 0xec, 0x27, 0x15, 0x92, 0x89, 0x42,
+	0xc2, 0x9b, 0x03, 0xc3, 0x5e, 0xe8, 0xda, 0x4c, 0x56, 0x9e, 0x8a, 0x95, 0xec, 0xcd, 0x00, 0x36,
+	0xe6, 0xcf, 0x5a, 0x73, 0x7e, 0xce, 0xd5, 0xe6, 0xfd, 0x9c, 0xbb, 0x06, 0x45, 0x39, 0x3e, 0xa9,
+	0x39, 0x5e, 0x6c, 0xd2, 0x3f, 0x2e, 0xe6, 0x33, 0x3f, 0x2e, 0x9a, 0x47, 0xa0, 0x4f, 0x8f, 0x64,
+	0xfc, 0x7e, 0x14, 0xf7, 0x31, 0xc5, 0xbe, 0x13, 0xf7, 0xd7, 0x09, 0x01, 0x6d, 0x40, 0xa9, 0x47,
+	0x06, 0x38, 0x8a, 0x8f, 0x50, 0x3b, 0xf3, 0x1e, 0xd4, 0xa7, 0x86, 0x2a, 0xfe, 0xa5, 0xc4, 0x4f,
+	0x34, 0xaa, 0x47, 0xf3, 0xb5, 0xf9, 0x25, 0x2c, 0x65, 0x86, 0x52, 0x0e, 0x12, 0xc9, 0xaf, 0x40,
+	0x7c, 0xfd, 0x83, 0x2e, 0x6a, 0x43, 0x7d, 0x2a, 0x92, 0xf8, 0x2b, 0x9c, 0xb7, 0x9f, 0xf8, 0x8a,
+	0xd2, 0x2e, 0xde, 0x91, 0x14, 0x42, 0x00, 0xec, 0xab, 0x6e, 0xd6, 0x07, 0xbc, 0xeb, 0x28, 0xc0,
+	0xfd, 0x16, 0x2c, 0x65, 0xda, 0x3f, 0x02, 0x28, 0xb5, 0x3b, 0xd6, 0xf1, 0xd9, 0x4b, 0x7d, 0x01,
+	0x55, 0xa1, 0xf8, 0xe2, 0xa4, 0xb5, 0xdf, 0xd1, 0x35, 0x54, 0x81, 0xc2, 0x41, 0xab, 0x75, 0xa2,
+	0xe7, 0x50, 0x19, 0xf2, 0xc7, 0x67, 0x1d, 0x3d, 0xcf, 0x49, 0xcf, 0xf7, 0x3b, 0x4d, 0xbd, 0x20,
+	0x64, 0x9a, 0x87, 0x56, 0xb3, 0xa3, 0x17, 0xef, 0x3f, 0xce, 0xfc, 0xdb, 0x41, 0xa8, 0x5c, 0x82,
+	0x6a, 0xf3, 0x17, 0x9d, 0xe6, 0x59, 0xfb, 0xb8, 0x75, 0xa6, 0x2f, 0x08, 0xb9, 0xe6, 0x69, 0x4b,
+	0x2a, 0x3d, 0x6d, 0xb6, 0x8f, 0xf4, 0xdc, 0xfd, 0xc7, 0x50, 0x89, 0x13, 0x8b, 0x9f, 0x7a, 0xdc,
+	0xee, 0x1c, 0xb7, 0xf4, 0x05, 0x54, 0x83, 0xf2, 0xc9, 0xf1, 0xd9, 0xd7, 0x4d, 0xeb, 0xb9, 0xae,
+	0x21, 0x1d, 0x16, 0xf7, 0x7f, 0xde, 0xee, 0xee, 0x9f, 0x9f, 0x77, 0xa5, 0xd4, 0x41, 0xe5, 0xfb,
+	0x7f, 0xdd, 0xd1, 0x7e, 0x99, 0xbb, 0x7c, 0x74, 0x51, 0x12, 0x0d, 0xe5, 0xd3, 0xff, 0x0c, 0x00,
+	0x9e, 0x03, 0x8c, 0x39, 0x8c, 0x19, 0x00, 0x00,
 }
 
 func (this *ApplicationSpec) Equal(that interface{}) bool {
@@ -2063,14 +2835,14 @@ func (this *VersionedApplicationSpec_InstallationSteps) Equal(that interface{})
 	}
 	return true
 }
-func (this *InstallationSteps) Equal(that interface{}) bool {
+func (this *VersionedApplicationSpec_LocalChart) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*InstallationSteps)
+	that1, ok := that.(*VersionedApplicationSpec_LocalChart)
 	if !ok {
-		that2, ok := that.(InstallationSteps)
+		that2, ok := that.(VersionedApplicationSpec_LocalChart)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2082,63 +2854,584 @@ func (this *InstallationSteps) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if len(this.Steps) != len(that1.Steps) {
+	if !this.LocalChart.Equal(that1.LocalChart) {
 		return false
 	}
-	for i := range this.Steps {
-		if !this.Steps[i].Equal(that1.Steps[i]) {
+	return true
+}
+func (this *VersionedApplicationSpec_LocalManifests) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*VersionedApplicationSpec_LocalManifests)
+	if !ok {
+		that2, ok := that.(VersionedApplicationSpec_LocalManifests)
+		if ok {
+			that1 = &that2
+		} else {
 			return false
 		}
 	}
-	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.LocalManifests.Equal(that1.LocalManifests) {
 		return false
 	}
 	return true
 }
-func (this *InstallationSteps_Step) Equal(that interface{}) bool {
+func (this *VersionedApplicationSpec_HelmRepository) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*InstallationSteps_Step)
+	that1, ok := that.(*VersionedApplicationSpec_HelmRepository)
 	if !ok {
-		that2, ok := that.(InstallationSteps_Step)
+		that2, ok := that.(VersionedApplicationSpec_HelmRepository)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.HelmRepository.Equal(that1.HelmRepository) {
+		return false
+	}
+	return true
+}
+func (this *VersionedApplicationSpec_OciChart) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*VersionedApplicationSpec_OciChart)
+	if !ok {
+		that2, ok := that.(VersionedApplicationSpec_OciChart)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.OciChart.Equal(that1.OciChart) {
+		return false
+	}
+	return true
+}
+func (this *VersionedApplicationSpec_GitChart) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*VersionedApplicationSpec_GitChart)
+	if !ok {
+		that2, ok := that.(VersionedApplicationSpec_GitChart)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.GitChart.Equal(that1.GitChart) {
+		return false
+	}
+	return true
+}
+func (this *VersionedApplicationSpec_InlineManifests) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*VersionedApplicationSpec_InlineManifests)
+	if !ok {
+		that2, ok := that.(VersionedApplicationSpec_InlineManifests)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.InlineManifests.Equal(that1.InlineManifests) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps)
+	if !ok {
+		that2, ok := that.(InstallationSteps)
 		if ok {
 			that1 = &that2
 		} else {
 			return false
 		}
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Steps) != len(that1.Steps) {
+		return false
+	}
+	for i := range this.Steps {
+		if !this.Steps[i].Equal(that1.Steps[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if that1.Step == nil {
+		if this.Step != nil {
+			return false
+		}
+	} else if this.Step == nil {
+		return false
+	} else if !this.Step.Equal(that1.Step) {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_GithubChart) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_GithubChart)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_GithubChart)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.GithubChart.Equal(that1.GithubChart) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_HelmArchive) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_HelmArchive)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_HelmArchive)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.HelmArchive.Equal(that1.HelmArchive) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_ManifestsArchive) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_ManifestsArchive)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_ManifestsArchive)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.ManifestsArchive.Equal(that1.ManifestsArchive) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_LocalChart) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_LocalChart)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_LocalChart)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.LocalChart.Equal(that1.LocalChart) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_LocalManifests) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_LocalManifests)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_LocalManifests)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.LocalManifests.Equal(that1.LocalManifests) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_HelmRepository) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_HelmRepository)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_HelmRepository)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.HelmRepository.Equal(that1.HelmRepository) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_OciChart) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_OciChart)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_OciChart)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.OciChart.Equal(that1.OciChart) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_GitChart) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_GitChart)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_GitChart)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.GitChart.Equal(that1.GitChart) {
+		return false
+	}
+	return true
+}
+func (this *InstallationSteps_Step_InlineManifests) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InstallationSteps_Step_InlineManifests)
+	if !ok {
+		that2, ok := that.(InstallationSteps_Step_InlineManifests)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.InlineManifests.Equal(that1.InlineManifests) {
+		return false
+	}
+	return true
+}
+func (this *Flavor) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Flavor)
+	if !ok {
+		that2, ok := that.(Flavor)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Description != that1.Description {
+		return false
+	}
+	if len(this.CustomizationLayers) != len(that1.CustomizationLayers) {
+		return false
+	}
+	for i := range this.CustomizationLayers {
+		if !this.CustomizationLayers[i].Equal(that1.CustomizationLayers[i]) {
+			return false
+		}
+	}
+	if len(this.RequirementSets) != len(that1.RequirementSets) {
+		return false
+	}
+	for i := range this.RequirementSets {
+		if !this.RequirementSets[i].Equal(that1.RequirementSets[i]) {
+			return false
+		}
+	}
+	if len(this.Parameters) != len(that1.Parameters) {
+		return false
+	}
+	for i := range this.Parameters {
+		if !this.Parameters[i].Equal(that1.Parameters[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *Layer) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Layer)
+	if !ok {
+		that2, ok := that.(Layer)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.DisplayName != that1.DisplayName {
+		return false
+	}
+	if this.Description != that1.Description {
+		return false
+	}
+	if this.Optional != that1.Optional {
+		return false
+	}
+	if len(this.Options) != len(that1.Options) {
+		return false
+	}
+	for i := range this.Options {
+		if !this.Options[i].Equal(that1.Options[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *LayerOption) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*LayerOption)
+	if !ok {
+		that2, ok := that.(LayerOption)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.DisplayName != that1.DisplayName {
+		return false
+	}
+	if this.Description != that1.Description {
+		return false
+	}
+	if this.HelmValues != that1.HelmValues {
+		return false
+	}
+	if len(this.Parameters) != len(that1.Parameters) {
+		return false
+	}
+	for i := range this.Parameters {
+		if !this.Parameters[i].Equal(that1.Parameters[i]) {
+			return false
+		}
+	}
+	if len(this.ResourceDependencies) != len(that1.ResourceDependencies) {
+		return false
+	}
+	for i := range this.ResourceDependencies {
+		if !this.ResourceDependencies[i].Equal(that1.ResourceDependencies[i]) {
+			return false
+		}
+	}
+	if !this.Kustomize.Equal(that1.Kustomize) {
 		return false
 	}
-	if this.Name != that1.Name {
+	if len(this.Patches) != len(that1.Patches) {
 		return false
 	}
-	if that1.Step == nil {
-		if this.Step != nil {
+	for i := range this.Patches {
+		if !this.Patches[i].Equal(that1.Patches[i]) {
 			return false
 		}
-	} else if this.Step == nil {
-		return false
-	} else if !this.Step.Equal(that1.Step) {
-		return false
 	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
 	return true
 }
-func (this *InstallationSteps_Step_GithubChart) Equal(that interface{}) bool {
+func (this *ResourcePatch) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*InstallationSteps_Step_GithubChart)
+	that1, ok := that.(*ResourcePatch)
 	if !ok {
-		that2, ok := that.(InstallationSteps_Step_GithubChart)
+		that2, ok := that.(ResourcePatch)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2150,19 +3443,31 @@ func (this *InstallationSteps_Step_GithubChart) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if !this.GithubChart.Equal(that1.GithubChart) {
+	if !this.Selector.Equal(that1.Selector) {
+		return false
+	}
+	if that1.Patch == nil {
+		if this.Patch != nil {
+			return false
+		}
+	} else if this.Patch == nil {
+		return false
+	} else if !this.Patch.Equal(that1.Patch) {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
 	return true
 }
-func (this *InstallationSteps_Step_HelmArchive) Equal(that interface{}) bool {
+func (this *ResourcePatch_JsonPatch) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*InstallationSteps_Step_HelmArchive)
+	that1, ok := that.(*ResourcePatch_JsonPatch)
 	if !ok {
-		that2, ok := that.(InstallationSteps_Step_HelmArchive)
+		that2, ok := that.(ResourcePatch_JsonPatch)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2174,19 +3479,19 @@ func (this *InstallationSteps_Step_HelmArchive) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if !this.HelmArchive.Equal(that1.HelmArchive) {
+	if this.JsonPatch != that1.JsonPatch {
 		return false
 	}
 	return true
 }
-func (this *InstallationSteps_Step_ManifestsArchive) Equal(that interface{}) bool {
+func (this *ResourcePatch_StrategicMergePatch) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*InstallationSteps_Step_ManifestsArchive)
+	that1, ok := that.(*ResourcePatch_StrategicMergePatch)
 	if !ok {
-		that2, ok := that.(InstallationSteps_Step_ManifestsArchive)
+		that2, ok := that.(ResourcePatch_StrategicMergePatch)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2198,19 +3503,19 @@ func (this *InstallationSteps_Step_ManifestsArchive) Equal(that interface{}) boo
 	} else if this == nil {
 		return false
 	}
-	if !this.ManifestsArchive.Equal(that1.ManifestsArchive) {
+	if this.StrategicMergePatch != that1.StrategicMergePatch {
 		return false
 	}
 	return true
 }
-func (this *Flavor) Equal(that interface{}) bool {
+func (this *ResourceSelector) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Flavor)
+	that1, ok := that.(*ResourceSelector)
 	if !ok {
-		that2, ok := that.(Flavor)
+		that2, ok := that.(ResourceSelector)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2222,49 +3527,31 @@ func (this *Flavor) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Name != that1.Name {
-		return false
-	}
-	if this.Description != that1.Description {
+	if this.Group != that1.Group {
 		return false
 	}
-	if len(this.CustomizationLayers) != len(that1.CustomizationLayers) {
+	if this.Kind != that1.Kind {
 		return false
 	}
-	for i := range this.CustomizationLayers {
-		if !this.CustomizationLayers[i].Equal(that1.CustomizationLayers[i]) {
-			return false
-		}
-	}
-	if len(this.RequirementSets) != len(that1.RequirementSets) {
+	if this.Name != that1.Name {
 		return false
 	}
-	for i := range this.RequirementSets {
-		if !this.RequirementSets[i].Equal(that1.RequirementSets[i]) {
-			return false
-		}
-	}
-	if len(this.Parameters) != len(that1.Parameters) {
+	if this.Namespace != that1.Namespace {
 		return false
 	}
-	for i := range this.Parameters {
-		if !this.Parameters[i].Equal(that1.Parameters[i]) {
-			return false
-		}
-	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
 	return true
 }
-func (this *Layer) Equal(that interface{}) bool {
+func (this *KustomizeOverlay) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Layer)
+	that1, ok := that.(*KustomizeOverlay)
 	if !ok {
-		that2, ok := that.(Layer)
+		that2, ok := that.(KustomizeOverlay)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2276,39 +3563,31 @@ func (this *Layer) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Id != that1.Id {
-		return false
-	}
-	if this.DisplayName != that1.DisplayName {
-		return false
-	}
-	if this.Description != that1.Description {
+	if that1.Location == nil {
+		if this.Location != nil {
+			return false
+		}
+	} else if this.Location == nil {
 		return false
-	}
-	if this.Optional != that1.Optional {
+	} else if !this.Location.Equal(that1.Location) {
 		return false
 	}
-	if len(this.Options) != len(that1.Options) {
+	if this.OverlayPath != that1.OverlayPath {
 		return false
 	}
-	for i := range this.Options {
-		if !this.Options[i].Equal(that1.Options[i]) {
-			return false
-		}
-	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
 	return true
 }
-func (this *LayerOption) Equal(that interface{}) bool {
+func (this *KustomizeOverlay_Github) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*LayerOption)
+	that1, ok := that.(*KustomizeOverlay_Github)
 	if !ok {
-		that2, ok := that.(LayerOption)
+		that2, ok := that.(KustomizeOverlay_Github)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2320,35 +3599,7 @@ func (this *LayerOption) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Id != that1.Id {
-		return false
-	}
-	if this.DisplayName != that1.DisplayName {
-		return false
-	}
-	if this.Description != that1.Description {
-		return false
-	}
-	if this.HelmValues != that1.HelmValues {
-		return false
-	}
-	if len(this.Parameters) != len(that1.Parameters) {
-		return false
-	}
-	for i := range this.Parameters {
-		if !this.Parameters[i].Equal(that1.Parameters[i]) {
-			return false
-		}
-	}
-	if len(this.ResourceDependencies) != len(that1.ResourceDependencies) {
-		return false
-	}
-	for i := range this.ResourceDependencies {
-		if !this.ResourceDependencies[i].Equal(that1.ResourceDependencies[i]) {
-			return false
-		}
-	}
-	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+	if !this.Github.Equal(that1.Github) {
 		return false
 	}
 	return true
@@ -2482,6 +3733,29 @@ func (this *Parameter) Equal(that interface{}) bool {
 	if this.DisplayName != that1.DisplayName {
 		return false
 	}
+	if len(this.AllowedValues) != len(that1.AllowedValues) {
+		return false
+	}
+	for i := range this.AllowedValues {
+		if this.AllowedValues[i] != that1.AllowedValues[i] {
+			return false
+		}
+	}
+	if this.Pattern != that1.Pattern {
+		return false
+	}
+	if !this.Minimum.Equal(that1.Minimum) {
+		return false
+	}
+	if !this.Maximum.Equal(that1.Maximum) {
+		return false
+	}
+	if !this.MinLength.Equal(that1.MinLength) {
+		return false
+	}
+	if !this.MaxLength.Equal(that1.MaxLength) {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -2958,6 +4232,39 @@ func (this *GithubRepositoryLocation) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *GitRepositoryLocation) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GitRepositoryLocation)
+	if !ok {
+		that2, ok := that.(GitRepositoryLocation)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Url != that1.Url {
+		return false
+	}
+	if this.Ref != that1.Ref {
+		return false
+	}
+	if this.Directory != that1.Directory {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
 func (this *TgzLocation) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2980,6 +4287,129 @@ func (this *TgzLocation) Equal(that interface{}) bool {
 	if this.Uri != that1.Uri {
 		return false
 	}
+	if this.Template != that1.Template {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *HelmRepositoryLocation) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*HelmRepositoryLocation)
+	if !ok {
+		that2, ok := that.(HelmRepositoryLocation)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.RepositoryUrl != that1.RepositoryUrl {
+		return false
+	}
+	if this.Chart != that1.Chart {
+		return false
+	}
+	if this.Version != that1.Version {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *OciChartLocation) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*OciChartLocation)
+	if !ok {
+		that2, ok := that.(OciChartLocation)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Reference != that1.Reference {
+		return false
+	}
+	if this.Digest != that1.Digest {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *InlineManifests) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InlineManifests)
+	if !ok {
+		that2, ok := that.(InlineManifests)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Yaml != that1.Yaml {
+		return false
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
+func (this *LocalLocation) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*LocalLocation)
+	if !ok {
+		that2, ok := that.(LocalLocation)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Path != that1.Path {
+		return false
+	}
+	if this.Template != that1.Template {
+		return false
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -3,6 +3,9 @@ module github.com/solo-io/service-mesh-hub
 go 1.13
 
 require (
+	github.com/Masterminds/semver/v3 v3.0.1
+	github.com/Masterminds/sprig/v3 v3.0.0
+	github.com/evanphx/json-patch v4.5.0+incompatible
 	github.com/ghodss/yaml v1.0.1-0.20190212211648-25d852aebe32
 	github.com/gogo/protobuf v1.3.1
 	github.com/golang/mock v1.4.0
@@ -25,6 +28,7 @@ require (
 	github.com/solo-io/solo-kit v0.13.2
 	github.com/spf13/afero v1.2.2
 	github.com/spf13/cobra v0.0.5
+	github.com/spf13/pflag v1.0.5
 	go.uber.org/zap v1.13.0
 	golang.org/x/crypto v0.0.0-20200221231518-2aa609cf4a9d // indirect
 	golang.org/x/net v0.0.0-20200226121028-0de0cce0169b // indirect
@@ -33,9 +37,11 @@ require (
 	golang.org/x/tools v0.0.0-20200226205201-eb7c56241bdb // indirect
 	gopkg.in/AlecAivazis/survey.v1 v1.8.2
 	gopkg.in/yaml.v2 v2.2.8 // indirect
+	helm.sh/helm/v3 v3.0.0
 	k8s.io/api v0.17.2
 	k8s.io/apimachinery v0.17.3
-	k8s.io/client-go v11.0.0+incompatible // indirect
+	k8s.io/client-go v11.0.0+incompatible
+	sigs.k8s.io/kustomize v2.0.3+incompatible
 	sigs.k8s.io/yaml v1.1.0
 )
 
@@ -0,0 +1,366 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/protoutils"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/registry"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	specsFilename     = "specs.yaml"
+	indexFilename     = "index.json"
+	artifactDirectory = "artifacts"
+
+	// Archive locations in a bundle's specs are rewritten to point at the bundle's artifacts with this scheme.
+	ArtifactScheme = "bundle://"
+)
+
+var (
+	ArtifactNotInBundleError = func(location string) error {
+		return errors.Errorf("%v is not part of the bundle", location)
+	}
+
+	InvalidBundleError = func(err error) error {
+		return errors.Wrapf(err, "invalid bundle")
+	}
+)
+
+// Maps the original artifact locations to their path in the bundle, so that specs which were not read from the bundle
+// (i.e. install spec files) can be rendered from it too.
+type Index struct {
+	Archives map[string]string `json:"archives"`
+	Github   map[string]string `json:"github"`
+	Local    map[string]string `json:"local"`
+	Oci      map[string]string `json:"oci"`
+	Git      map[string]string `json:"git"`
+}
+
+// An air-gapped bundle of application specs and every chart and archive they reference.
+// A bundle is both a spec reader and an artifact fetcher, so that specs can be rendered from it without network
+// access.
+type Bundle struct {
+	specs     []*v1.ApplicationSpec
+	index     Index
+	artifacts map[string][]byte
+}
+
+var _ registry.SpecReader = &Bundle{}
+var _ render.ArtifactFetcher = &Bundle{}
+
+// Keeps the specs named name, and only their version matching version. Empty filters match everything.
+func SelectSpecs(specs []*v1.ApplicationSpec, name, version string) []*v1.ApplicationSpec {
+	var selected []*v1.ApplicationSpec
+	for _, spec := range specs {
+		if name != "" && spec.GetName() != name {
+			continue
+		}
+		spec = proto.Clone(spec).(*v1.ApplicationSpec)
+		var versions []*v1.VersionedApplicationSpec
+		for _, versionedSpec := range spec.GetVersions() {
+			if version == "" || versionedSpec.GetVersion() == version {
+				versions = append(versions, versionedSpec)
+			}
+		}
+		if len(versions) == 0 {
+			continue
+		}
+		spec.Versions = versions
+		selected = append(selected, spec)
+	}
+	return selected
+}
+
+// Fetches every artifact referenced by the specs and writes them to w as a gzipped tarball, along with copies of the
+// specs in which archive locations point at the bundled artifacts.
+func Create(ctx context.Context, fetcher render.ArtifactFetcher, specs []*v1.ApplicationSpec, w io.Writer) error {
+	b := &Bundle{
+		index: Index{
+			Archives: make(map[string]string),
+			Github:   make(map[string]string),
+			Local:    make(map[string]string),
+			Oci:      make(map[string]string),
+			Git:      make(map[string]string),
+		},
+		artifacts: make(map[string][]byte),
+	}
+	add := func(content []byte) string {
+		sum := sha256.Sum256(content)
+		artifactPath := path.Join(artifactDirectory, hex.EncodeToString(sum[:]))
+		b.artifacts[artifactPath] = content
+		return artifactPath
+	}
+
+	for _, spec := range specs {
+		spec = proto.Clone(spec).(*v1.ApplicationSpec)
+		for _, version := range spec.GetVersions() {
+			contextutils.LoggerFrom(ctx).Infow("Bundling artifacts",
+				zap.String("application", spec.GetName()),
+				zap.String("version", version.GetVersion()))
+			err := render.VisitArtifacts(version, render.ArtifactVisitor{
+				Archive: func(location *v1.TgzLocation) error {
+					content, err := fetcher.FetchArchive(ctx, location.GetUri())
+					if err != nil {
+						return err
+					}
+					artifactPath := add(content)
+					b.index.Archives[location.GetUri()] = artifactPath
+					location.Uri = ArtifactScheme + artifactPath
+					return nil
+				},
+				Github: func(location *v1.GithubRepositoryLocation) error {
+					content, err := fetcher.FetchGithubArchive(ctx, location)
+					if err != nil {
+						return err
+					}
+					b.index.Github[githubKey(location)] = add(content)
+					return nil
+				},
+				HelmRepository: func(location *v1.HelmRepositoryLocation) error {
+					// Charts are resolved from the bundled repository index when rendering, so record the index and
+					// the chart under their original urls.
+					_, err := render.FetchHelmRepositoryChart(ctx, &recordingFetcher{ArtifactFetcher: fetcher, bundle: b, add: add}, location)
+					return err
+				},
+				OciChart: func(location *v1.OciChartLocation) error {
+					content, err := fetcher.FetchOciChart(ctx, location)
+					if err != nil {
+						return err
+					}
+					b.index.Oci[ociKey(location)] = add(content)
+					return nil
+				},
+				Git: func(location *v1.GitRepositoryLocation) error {
+					content, err := fetcher.FetchGitArchive(ctx, location)
+					if err != nil {
+						return err
+					}
+					b.index.Git[gitKey(location)] = add(content)
+					return nil
+				},
+				Local: func(location *v1.LocalLocation) error {
+					content, err := fetcher.FetchLocalDirectory(ctx, location.GetPath())
+					if err != nil {
+						return err
+					}
+					b.index.Local[location.GetPath()] = add(content)
+					return nil
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		b.specs = append(b.specs, spec)
+	}
+	return b.write(w)
+}
+
+// Reads a bundle from the content of a bundle archive.
+func Load(content []byte) (*Bundle, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, InvalidBundleError(err)
+	}
+	defer gzr.Close()
+
+	b := &Bundle{artifacts: make(map[string][]byte)}
+	var specsYaml, indexJson []byte
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, InvalidBundleError(err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		fileContent, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, InvalidBundleError(err)
+		}
+		switch {
+		case header.Name == specsFilename:
+			specsYaml = fileContent
+		case header.Name == indexFilename:
+			indexJson = fileContent
+		case strings.HasPrefix(header.Name, artifactDirectory+"/"):
+			b.artifacts[header.Name] = fileContent
+		}
+	}
+	if specsYaml == nil || indexJson == nil {
+		return nil, InvalidBundleError(errors.Errorf("missing %v or %v", specsFilename, indexFilename))
+	}
+
+	var specs v1.ApplicationSpecs
+	if err := protoutils.UnmarshalYaml(specsYaml, &specs); err != nil {
+		return nil, InvalidBundleError(err)
+	}
+	b.specs = specs.Specs
+	if err := json.Unmarshal(indexJson, &b.index); err != nil {
+		return nil, InvalidBundleError(err)
+	}
+	return b, nil
+}
+
+// Reads a bundle from a bundle archive on disk.
+func LoadFile(filename string) (*Bundle, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Load(content)
+}
+
+func (b *Bundle) GetSpecs() ([]*v1.ApplicationSpec, error) {
+	return b.specs, nil
+}
+
+func (b *Bundle) FetchArchive(_ context.Context, uri string) ([]byte, error) {
+	artifactPath := b.index.Archives[uri]
+	if strings.HasPrefix(uri, ArtifactScheme) {
+		artifactPath = strings.TrimPrefix(uri, ArtifactScheme)
+	}
+	content, ok := b.artifacts[artifactPath]
+	if !ok {
+		return nil, ArtifactNotInBundleError(uri)
+	}
+	return content, nil
+}
+
+func (b *Bundle) FetchGithubArchive(_ context.Context, location *v1.GithubRepositoryLocation) ([]byte, error) {
+	key := githubKey(location)
+	content, ok := b.artifacts[b.index.Github[key]]
+	if !ok {
+		return nil, ArtifactNotInBundleError(key)
+	}
+	return content, nil
+}
+
+func (b *Bundle) FetchLocalDirectory(_ context.Context, path string) ([]byte, error) {
+	content, ok := b.artifacts[b.index.Local[path]]
+	if !ok {
+		return nil, ArtifactNotInBundleError(path)
+	}
+	return content, nil
+}
+
+func (b *Bundle) FetchOciChart(_ context.Context, location *v1.OciChartLocation) ([]byte, error) {
+	key := ociKey(location)
+	content, ok := b.artifacts[b.index.Oci[key]]
+	if !ok {
+		return nil, ArtifactNotInBundleError(key)
+	}
+	return content, nil
+}
+
+func (b *Bundle) FetchGitArchive(_ context.Context, location *v1.GitRepositoryLocation) ([]byte, error) {
+	key := gitKey(location)
+	content, ok := b.artifacts[b.index.Git[key]]
+	if !ok {
+		return nil, ArtifactNotInBundleError(key)
+	}
+	return content, nil
+}
+
+func (b *Bundle) write(w io.Writer) error {
+	specsJson, err := protoutils.MarshalBytes(&v1.ApplicationSpecs{Specs: b.specs})
+	if err != nil {
+		return err
+	}
+	specsYaml, err := yaml.JSONToYAML(specsJson)
+	if err != nil {
+		return err
+	}
+	indexJson, err := json.MarshalIndent(b.index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		specsFilename: specsYaml,
+		indexFilename: indexJson,
+	}
+	for artifactPath, content := range b.artifacts {
+		files[artifactPath] = content
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	modTime := time.Now()
+	for _, name := range names {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(files[name])),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gzw.Close()
+}
+
+// Records the archives it fetches in the bundle's index.
+type recordingFetcher struct {
+	render.ArtifactFetcher
+	bundle *Bundle
+	add    func(content []byte) string
+}
+
+func (f *recordingFetcher) FetchArchive(ctx context.Context, uri string) ([]byte, error) {
+	content, err := f.ArtifactFetcher.FetchArchive(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	f.bundle.index.Archives[uri] = f.add(content)
+	return content, nil
+}
+
+func githubKey(location *v1.GithubRepositoryLocation) string {
+	return location.GetOrg() + "/" + location.GetRepo() + "@" + location.GetRef()
+}
+
+func ociKey(location *v1.OciChartLocation) string {
+	if location.GetDigest() == "" {
+		return location.GetReference()
+	}
+	return location.GetReference() + "@" + location.GetDigest()
+}
+
+func gitKey(location *v1.GitRepositoryLocation) string {
+	return location.GetUrl() + "@" + location.GetRef()
+}
@@ -0,0 +1,110 @@
+package bundle_test
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/bundle"
+)
+
+type fakeFetcher struct {
+	archives map[string]string
+	fetches  int
+}
+
+func (f *fakeFetcher) FetchArchive(_ context.Context, uri string) ([]byte, error) {
+	f.fetches++
+	return []byte(f.archives[uri]), nil
+}
+
+func (f *fakeFetcher) FetchGithubArchive(_ context.Context, location *v1.GithubRepositoryLocation) ([]byte, error) {
+	f.fetches++
+	return []byte(f.archives[location.Repo]), nil
+}
+
+func (f *fakeFetcher) FetchLocalDirectory(_ context.Context, path string) ([]byte, error) {
+	f.fetches++
+	return []byte(f.archives[path]), nil
+}
+
+func (f *fakeFetcher) FetchOciChart(_ context.Context, location *v1.OciChartLocation) ([]byte, error) {
+	f.fetches++
+	return []byte(f.archives[location.Reference]), nil
+}
+
+func (f *fakeFetcher) FetchGitArchive(_ context.Context, location *v1.GitRepositoryLocation) ([]byte, error) {
+	f.fetches++
+	return []byte(f.archives[location.Url]), nil
+}
+
+var _ = Describe("bundle", func() {
+
+	var (
+		fetcher *fakeFetcher
+		specs   []*v1.ApplicationSpec
+	)
+
+	BeforeEach(func() {
+		fetcher = &fakeFetcher{archives: map[string]string{
+			"https://example.com/chart.tgz": "chart",
+			"charts":                        "repository",
+		}}
+		specs = []*v1.ApplicationSpec{{
+			Name: "app",
+			Versions: []*v1.VersionedApplicationSpec{
+				{
+					Version: "1.0.0",
+					InstallationSpec: &v1.VersionedApplicationSpec_HelmArchive{
+						HelmArchive: &v1.TgzLocation{Uri: "https://example.com/chart.tgz"},
+					},
+				},
+				{
+					Version: "2.0.0",
+					InstallationSpec: &v1.VersionedApplicationSpec_GithubChart{
+						GithubChart: &v1.GithubRepositoryLocation{Org: "example", Repo: "charts", Ref: "master"},
+					},
+				},
+			},
+		}}
+	})
+
+	It("selects applications and versions", func() {
+		selected := bundle.SelectSpecs(specs, "app", "2.0.0")
+		Expect(selected).To(HaveLen(1))
+		Expect(selected[0].Versions).To(HaveLen(1))
+		Expect(selected[0].Versions[0].Version).To(Equal("2.0.0"))
+		Expect(specs[0].Versions).To(HaveLen(2))
+		Expect(bundle.SelectSpecs(specs, "other", "")).To(BeEmpty())
+	})
+
+	It("serves the specs and artifacts it was created from", func() {
+		buf := new(bytes.Buffer)
+		Expect(bundle.Create(context.TODO(), fetcher, specs, buf)).To(Succeed())
+		b, err := bundle.Load(buf.Bytes())
+		Expect(err).NotTo(HaveOccurred())
+
+		bundledSpecs, err := b.GetSpecs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bundledSpecs).To(HaveLen(1))
+		uri := bundledSpecs[0].Versions[0].GetHelmArchive().GetUri()
+		Expect(uri).To(HavePrefix(bundle.ArtifactScheme))
+		Expect(specs[0].Versions[0].GetHelmArchive().GetUri()).To(Equal("https://example.com/chart.tgz"))
+
+		content, err := b.FetchArchive(context.TODO(), uri)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("chart"))
+		content, err = b.FetchArchive(context.TODO(), "https://example.com/chart.tgz")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("chart"))
+		content, err = b.FetchGithubArchive(context.TODO(), bundledSpecs[0].Versions[1].GetGithubChart())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("repository"))
+
+		_, err = b.FetchArchive(context.TODO(), "https://example.com/other.tgz")
+		Expect(err).To(HaveOccurred())
+		Expect(fetcher.fetches).To(Equal(2))
+	})
+})
@@ -0,0 +1,318 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	errors "github.com/rotisserie/eris"
+)
+
+const (
+	indexFilename = "index.json"
+	blobDirectory = "blobs"
+
+	DefaultMaxSize = 2 << 30 // 2GiB
+	DefaultMaxAge  = 24 * time.Hour
+)
+
+// Path elements of the default cache directory, relative to the user's home directory.
+var DefaultDirectoryElements = []string{".hubctl", "cache"}
+
+var (
+	DigestMismatchError = func(location, expected, actual string) error {
+		return errors.Errorf("digest of %v does not match: expected %v, found %v", location, expected, actual)
+	}
+
+	FailedToReadIndexError = func(err error) error {
+		return errors.Wrapf(err, "error reading cache index")
+	}
+)
+
+// An entry describes an artifact stored in the cache.
+type Entry struct {
+	// Key under which the artifact was stored, derived from its location
+	Key string `json:"key"`
+	// Human-readable location of the artifact, i.e. a url
+	Location string `json:"location"`
+	// sha256 digest of the artifact's content
+	Digest string `json:"digest"`
+	Size   int64  `json:"size"`
+	// Immutable entries are never considered stale, i.e. artifacts referenced by a commit sha or a digest
+	Immutable bool      `json:"immutable"`
+	Created   time.Time `json:"created"`
+	LastUsed  time.Time `json:"lastUsed"`
+}
+
+// Content-addressed, on-disk cache for downloaded artifacts.
+// Artifacts are stored once per content digest and indexed by a key derived from their location.
+// Once the total size of the stored artifacts exceeds MaxSize, the least recently used entries are evicted.
+// Mutable entries older than MaxAge are considered stale and are fetched again.
+type ArtifactCache struct {
+	Dir     string
+	MaxSize int64
+	MaxAge  time.Duration
+
+	lock sync.Mutex
+}
+
+func NewArtifactCache(dir string) *ArtifactCache {
+	return &ArtifactCache{
+		Dir:     dir,
+		MaxSize: DefaultMaxSize,
+		MaxAge:  DefaultMaxAge,
+	}
+}
+
+// Returns the cache directory in the user's home directory.
+func DefaultDirectory() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{home}, DefaultDirectoryElements...)...), nil
+}
+
+// Derives a cache key from the elements of an artifact's location.
+func Key(locationElements ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(locationElements, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns the cached artifact for the key, calling fetch to retrieve and store it if it is missing or stale.
+// If the digest of the artifact is known, a cached artifact with that digest is used regardless of its key, and
+// the fetched content is verified against it.
+func (c *ArtifactCache) Get(key, location, digest string, immutable bool, fetch func() ([]byte, error)) ([]byte, error) {
+	if content, ok := c.lookup(key, digest); ok {
+		return content, nil
+	}
+
+	content, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	actual := digestOf(content)
+	if digest != "" && digest != actual {
+		return nil, DigestMismatchError(location, digest, actual)
+	}
+	if err := c.store(Entry{
+		Key:       key,
+		Location:  location,
+		Digest:    actual,
+		Size:      int64(len(content)),
+		Immutable: immutable || digest != "",
+	}, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// Lists the entries in the cache, most recently used first.
+func (c *ArtifactCache) List() ([]Entry, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entries, err := c.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	sortByLastUsed(entries)
+	return entries, nil
+}
+
+// Removes stale entries and evicts the least recently used entries until the cache fits in maxSize bytes.
+// Returns the removed entries.
+func (c *ArtifactCache) Prune(maxSize int64) ([]Entry, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entries, err := c.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var kept, removed []Entry
+	for _, entry := range entries {
+		if c.isStale(entry) {
+			removed = append(removed, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	kept, evicted := evict(kept, maxSize)
+	removed = append(removed, evicted...)
+	return removed, c.writeIndexAndCollect(kept)
+}
+
+// Removes every entry from the cache.
+func (c *ArtifactCache) Clear() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return os.RemoveAll(c.Dir)
+}
+
+func (c *ArtifactCache) lookup(key, digest string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entries, err := c.readIndex()
+	if err != nil {
+		return nil, false
+	}
+	for i, entry := range entries {
+		byDigest := digest != "" && entry.Digest == digest
+		byKey := digest == "" && entry.Key == key && !c.isStale(entry)
+		if !byDigest && !byKey {
+			continue
+		}
+		content, err := ioutil.ReadFile(c.blobPath(entry.Digest))
+		if err != nil || digestOf(content) != entry.Digest {
+			return nil, false
+		}
+		entries[i].LastUsed = time.Now()
+		_ = c.writeIndex(entries)
+		return content, true
+	}
+	return nil, false
+}
+
+func (c *ArtifactCache) store(entry Entry, content []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := os.MkdirAll(filepath.Join(c.Dir, blobDirectory), 0755); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.blobPath(entry.Digest), content); err != nil {
+		return err
+	}
+
+	entries, err := c.readIndex()
+	if err != nil {
+		// A corrupt index only loses track of cached artifacts, so start over rather than failing the fetch.
+		entries = nil
+	}
+	entry.Created = time.Now()
+	entry.LastUsed = entry.Created
+	var updated []Entry
+	for _, existing := range entries {
+		if existing.Key != entry.Key {
+			updated = append(updated, existing)
+		}
+	}
+	updated, _ = evict(append(updated, entry), c.MaxSize)
+	return c.writeIndexAndCollect(updated)
+}
+
+func (c *ArtifactCache) isStale(entry Entry) bool {
+	return !entry.Immutable && c.MaxAge > 0 && time.Since(entry.Created) > c.MaxAge
+}
+
+func (c *ArtifactCache) blobPath(digest string) string {
+	return filepath.Join(c.Dir, blobDirectory, digest)
+}
+
+func (c *ArtifactCache) readIndex() ([]Entry, error) {
+	content, err := ioutil.ReadFile(filepath.Join(c.Dir, indexFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, FailedToReadIndexError(err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, FailedToReadIndexError(err)
+	}
+	return entries, nil
+}
+
+func (c *ArtifactCache) writeIndex(entries []Entry) error {
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(c.Dir, indexFilename), content)
+}
+
+// Writes the index, then removes the blobs that are no longer referenced by it.
+func (c *ArtifactCache) writeIndexAndCollect(entries []Entry) error {
+	if err := c.writeIndex(entries); err != nil {
+		return err
+	}
+	referenced := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		referenced[entry.Digest] = true
+	}
+	blobs, err := ioutil.ReadDir(filepath.Join(c.Dir, blobDirectory))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, blob := range blobs {
+		if !referenced[blob.Name()] {
+			if err := os.Remove(c.blobPath(blob.Name())); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Evicts the least recently used entries until the total size of the distinct blobs fits in maxSize.
+// A maxSize of zero or less disables eviction.
+func evict(entries []Entry, maxSize int64) (kept []Entry, evicted []Entry) {
+	if maxSize <= 0 {
+		return entries, nil
+	}
+	sortByLastUsed(entries)
+	blobSizes := make(map[string]int64)
+	var total int64
+	for _, entry := range entries {
+		if _, ok := blobSizes[entry.Digest]; !ok && total+entry.Size > maxSize {
+			evicted = append(evicted, entry)
+			continue
+		}
+		if _, ok := blobSizes[entry.Digest]; !ok {
+			blobSizes[entry.Digest] = entry.Size
+			total += entry.Size
+		}
+		kept = append(kept, entry)
+	}
+	return kept, evicted
+}
+
+func sortByLastUsed(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+}
+
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func writeFileAtomic(filename string, content []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,100 @@
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/service-mesh-hub/pkg/cache"
+)
+
+var _ = Describe("artifact cache", func() {
+
+	var (
+		dir           string
+		artifactCache *cache.ArtifactCache
+		fetches       int
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "artifact-cache-")
+		Expect(err).NotTo(HaveOccurred())
+		artifactCache = cache.NewArtifactCache(dir)
+		fetches = 0
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	fetch := func(content string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			fetches++
+			return []byte(content), nil
+		}
+	}
+
+	It("only fetches an artifact once", func() {
+		for i := 0; i < 2; i++ {
+			content, err := artifactCache.Get(cache.Key("archive", "a"), "a", "", false, fetch("content"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("content"))
+		}
+		Expect(fetches).To(Equal(1))
+	})
+
+	It("fetches stale mutable artifacts again", func() {
+		artifactCache.MaxAge = time.Nanosecond
+		for i := 0; i < 2; i++ {
+			_, err := artifactCache.Get(cache.Key("archive", "a"), "a", "", false, fetch("content"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = artifactCache.Get(cache.Key("github", "b"), "b", "", true, fetch("pinned"))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(fetches).To(Equal(3))
+	})
+
+	It("rejects content that does not match the expected digest", func() {
+		_, err := artifactCache.Get(cache.Key("archive", "a"), "a", cache.Key("other"), false, fetch("content"))
+		Expect(err).To(HaveOccurred())
+		entries, err := artifactCache.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("evicts the least recently used artifacts once the cache is full", func() {
+		artifactCache.MaxSize = 10
+		_, err := artifactCache.Get(cache.Key("archive", "a"), "a", "", false, fetch("aaaaaa"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = artifactCache.Get(cache.Key("archive", "b"), "b", "", false, fetch("bbbbbb"))
+		Expect(err).NotTo(HaveOccurred())
+
+		entries, err := artifactCache.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Location).To(Equal("b"))
+		blobs, err := ioutil.ReadDir(dir + "/blobs")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blobs).To(HaveLen(1))
+	})
+
+	It("prunes the cache down to the given size", func() {
+		_, err := artifactCache.Get(cache.Key("archive", "a"), "a", "", false, fetch("aaaaaa"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = artifactCache.Get(cache.Key("archive", "b"), "b", "", false, fetch("bbbbbb"))
+		Expect(err).NotTo(HaveOccurred())
+
+		removed, err := artifactCache.Prune(6)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(HaveLen(1))
+		Expect(removed[0].Location).To(Equal("a"))
+
+		Expect(artifactCache.Clear()).To(Succeed())
+		entries, err := artifactCache.List()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+})
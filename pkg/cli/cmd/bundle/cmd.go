@@ -0,0 +1,85 @@
+package bundle
+
+import (
+	"bytes"
+
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/service-mesh-hub/pkg/bundle"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/render"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/options"
+	"github.com/solo-io/service-mesh-hub/pkg/util"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func Cmd(o *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "create and render from air-gapped bundles of application specs and their artifacts",
+	}
+	cmd.AddCommand(
+		createCmd(o),
+		renderCmd(o))
+	return cmd
+}
+
+func createCmd(o *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "write application specs and every chart and archive they reference into a bundle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return create(o)
+		},
+	}
+	pflags := cmd.PersistentFlags()
+	pflags.StringVarP(&o.Bundle.File, "bundle", "b", "bundle.tgz",
+		"destination of the bundle")
+	pflags.StringVar(&o.Bundle.ApplicationName, "name", "",
+		"optional, only bundle the application with this name")
+	pflags.StringVar(&o.Bundle.Version, "version", "",
+		"optional, only bundle this version of the application")
+	options.AddRegistryFlags(pflags, o)
+	options.AddCacheFlags(pflags, o)
+	return cmd
+}
+
+func create(o *options.Options) error {
+	specs, err := options.MustGetSpecReader(o).GetSpecs()
+	if err != nil {
+		return err
+	}
+	specs = bundle.SelectSpecs(specs, o.Bundle.ApplicationName, o.Bundle.Version)
+	buf := new(bytes.Buffer)
+	if err := bundle.Create(o.Ctx, options.GetArtifactFetcher(o), specs, buf); err != nil {
+		return err
+	}
+	contextutils.LoggerFrom(o.Ctx).Infow("Created bundle",
+		zap.String("file", o.Bundle.File),
+		zap.Int("applications", len(specs)))
+	return util.SaveFile(o.Bundle.File, buf.String())
+}
+
+func renderCmd(o *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "render a manifest from a bundle, without network access",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := bundle.LoadFile(o.Bundle.File)
+			if err != nil {
+				return err
+			}
+			return render.Render(o, b, options.GetManifestRendererWithFetcher(o, b))
+		},
+	}
+	pflags := cmd.PersistentFlags()
+	pflags.StringVarP(&o.Bundle.File, "bundle", "b", "bundle.tgz",
+		"bundle to render from")
+	pflags.StringVarP(&o.InstallSpecFile, "install-spec-file", "i", "",
+		"optional install spec to generate manifests from")
+	pflags.StringVarP(&o.ManifestFile, "manifest-file", "m", "",
+		"optional destination for rendered manifest, otherwise print to stdout")
+	options.AddCapabilitiesFlags(pflags, o)
+	options.AddTransformFlags(pflags, o)
+	options.AddSecretFlags(pflags, o)
+	return cmd
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/options"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func Cmd(o *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "manage the cache of downloaded charts and archives",
+	}
+	pflags := cmd.PersistentFlags()
+	pflags.StringVar(&o.Cache.Directory, "cache-dir", "",
+		"directory in which downloaded charts and archives are cached, defaults to ~/.hubctl/cache")
+	cmd.AddCommand(
+		listCmd(o),
+		pruneCmd(o),
+		clearCmd(o),
+		warmCmd(o))
+	return cmd
+}
+
+func listCmd(o *options.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "list the cached artifacts, most recently used first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			artifactCache, err := options.GetArtifactCache(o)
+			if err != nil {
+				return err
+			}
+			entries, err := artifactCache.List()
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "LOCATION\tDIGEST\tSIZE\tIMMUTABLE\tLAST USED")
+			for _, entry := range entries {
+				fmt.Fprintf(w, "%v\t%.12v\t%v\t%v\t%v\n", entry.Location, entry.Digest, entry.Size, entry.Immutable,
+					entry.LastUsed.Format(time.RFC3339))
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func pruneCmd(o *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "remove stale artifacts and evict the least recently used ones until the cache fits in its maximum size",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			artifactCache, err := options.GetArtifactCache(o)
+			if err != nil {
+				return err
+			}
+			removed, err := artifactCache.Prune(artifactCache.MaxSize)
+			if err != nil {
+				return err
+			}
+			for _, entry := range removed {
+				fmt.Printf("removed %v\n", entry.Location)
+			}
+			return nil
+		},
+	}
+	pflags := cmd.PersistentFlags()
+	pflags.Int64Var(&o.Cache.MaxSizeMb, "max-size", options.CacheDefaults.MaxSizeMb,
+		"maximum size of the cache, in megabytes")
+	pflags.DurationVar(&o.Cache.MaxAge, "max-age", options.CacheDefaults.MaxAge,
+		"age after which artifacts that are not pinned to a commit or digest are considered stale")
+	return cmd
+}
+
+func clearCmd(o *options.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "remove every cached artifact",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			artifactCache, err := options.GetArtifactCache(o)
+			if err != nil {
+				return err
+			}
+			return artifactCache.Clear()
+		},
+	}
+}
+
+func warmCmd(o *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "warm",
+		Short: "download the artifacts referenced by the registry's application specs into the cache",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return warm(o)
+		},
+	}
+	pflags := cmd.PersistentFlags()
+	options.AddRegistryFlags(pflags, o)
+	pflags.StringVar(&o.Cache.Warm.ApplicationName, "name", "",
+		"optional, only warm the cache for the application with this name")
+	pflags.StringVar(&o.Cache.Warm.Version, "version", "",
+		"optional, only warm the cache for this version of the application")
+	return cmd
+}
+
+func warm(o *options.Options) error {
+	specs, err := options.MustGetSpecReader(o).GetSpecs()
+	if err != nil {
+		return err
+	}
+	fetcher := options.GetArtifactFetcher(o)
+	for _, spec := range specs {
+		if o.Cache.Warm.ApplicationName != "" && spec.GetName() != o.Cache.Warm.ApplicationName {
+			continue
+		}
+		for _, version := range spec.GetVersions() {
+			if o.Cache.Warm.Version != "" && version.GetVersion() != o.Cache.Warm.Version {
+				continue
+			}
+			if err := render.FetchArtifacts(o.Ctx, fetcher, version); err != nil {
+				return err
+			}
+			contextutils.LoggerFrom(o.Ctx).Infow("Cached artifacts",
+				zap.String("application", spec.GetName()),
+				zap.String("version", version.GetVersion()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package explain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/installspec"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/options"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputTable = "table"
+	outputJson  = "json"
+)
+
+var (
+	UnknownOutputError = func(output string) error {
+		return errors.Errorf("unknown output %v, expected one of %v, %v", output, outputTable, outputJson)
+	}
+)
+
+func Cmd(o *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain-values",
+		Short: "explain where each of the helm values of an installation comes from",
+		Long: "Prints, for every value passed to the chart of an installation, whether it comes from the spec's values, " +
+			"a layer option, a parameter or the user values, along with the values it overrides.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return explainValues(o)
+		},
+	}
+	pflags := cmd.PersistentFlags()
+	options.AddRegistryFlags(pflags, o)
+	pflags.StringVarP(&o.InstallSpecFile, "install-spec-file", "i", "",
+		"optional install spec to explain the values of, otherwise select an application interactively")
+	pflags.StringVarP(&o.InstallNamespace, "namespace", "n", "default",
+		"install namespace when selecting an application interactively")
+	pflags.StringVarP(&o.ExplainValues.Output, "output", "o", outputTable,
+		fmt.Sprintf("output format: %v or %v", outputTable, outputJson))
+	options.AddCapabilitiesFlags(pflags, o)
+	options.AddSecretFlags(pflags, o)
+	return cmd
+}
+
+func explainValues(o *options.Options) error {
+	if o.ExplainValues.Output != outputTable && o.ExplainValues.Output != outputJson {
+		return UnknownOutputError(o.ExplainValues.Output)
+	}
+
+	secrets, err := options.GetSecretResolver(o)
+	if err != nil {
+		return err
+	}
+	installSpec := &installspec.InstallSpec{}
+	if o.InstallSpecFile == "" {
+		if installSpec, err = installspec.GetInstallSpec(options.MustGetSpecReader(o), o.InstallNamespace, secrets); err != nil {
+			return err
+		}
+	} else if err := installSpec.Load(o.InstallSpecFile); err != nil {
+		return err
+	}
+	if err := installSpec.ResolveSecretDefaults(secrets); err != nil {
+		return err
+	}
+
+	options.ApplyCapabilities(o, &installSpec.Values)
+	explained, err := render.ExplainValues(o.Ctx, installSpec.Values, installSpec.Version)
+	if err != nil {
+		return err
+	}
+
+	if o.ExplainValues.Output == outputJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(explained)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tVALUE\tSOURCE\tOVERRIDES")
+	for _, provenance := range explained {
+		var overridden []string
+		for _, source := range provenance.Overridden {
+			overridden = append(overridden, fmt.Sprintf("%v=%v", formatSource(source), formatValue(source.Value)))
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", provenance.Path, formatValue(provenance.Source.Value),
+			formatSource(provenance.Source), strings.Join(overridden, ", "))
+	}
+	return w.Flush()
+}
+
+func formatSource(source render.ValueSource) string {
+	if source.Name == "" {
+		return string(source.Type)
+	}
+	return fmt.Sprintf("%v %v", source.Type, source.Name)
+}
+
+// Prints scalars as they are and tables and lists as JSON, on a single line.
+func formatValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
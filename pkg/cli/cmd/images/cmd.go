@@ -0,0 +1,237 @@
+package images
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	errors "github.com/rotisserie/eris"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/bundle"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/options"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/service-mesh-hub/pkg/render/util"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputTable = "table"
+	outputJson  = "json"
+	outputList  = "list"
+)
+
+var (
+	UnknownOutputError = func(output string) error {
+		return errors.Errorf("unknown output %v, expected one of %v, %v, %v", output, outputTable, outputJson, outputList)
+	}
+
+	FailedToRenderCombinationError = func(err error, usage imageUsage) error {
+		return errors.Wrapf(err, "failed to render %v version %v flavor %v with layers [%v]",
+			usage.Application, usage.Version, usage.Flavor, strings.Join(usage.Layers, ", "))
+	}
+
+	MissingRequiredParamsError = func(names []string, usage imageUsage) error {
+		return errors.Errorf("skipped %v version %v flavor %v with layers [%v]: required parameters %v have no default, "+
+			"set them with --params", usage.Application, usage.Version, usage.Flavor, strings.Join(usage.Layers, ", "),
+			strings.Join(names, ", "))
+	}
+
+	NoCombinationRenderedError = errors.Errorf("none of the flavor and layer combinations could be rendered")
+)
+
+func Cmd(o *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "images",
+		Short: "list the container images deployed by every flavor and layer combination of the registry's applications",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listImages(o)
+		},
+	}
+	pflags := cmd.PersistentFlags()
+	pflags.StringVar(&o.ListImages.ApplicationName, "name", "",
+		"optional, only list the images of the application with this name")
+	pflags.StringVar(&o.ListImages.Version, "version", "",
+		"optional, only list the images of this version of the application")
+	pflags.StringVarP(&o.ListImages.Output, "output", "o", outputTable,
+		fmt.Sprintf("output format: %v, %v or %v, which prints each distinct image once", outputTable, outputJson, outputList))
+	pflags.BoolVar(&o.ListImages.Dedupe, "dedupe", false,
+		"if set, print each distinct image once, along with the applications that deploy it")
+	pflags.StringToStringVar(&o.ListImages.Params, "params", nil,
+		"parameter values to render with, overriding the defaults from the specs")
+	pflags.StringVarP(&o.InstallNamespace, "namespace", "n", "default",
+		"namespace to render the applications in")
+	options.AddRegistryFlags(pflags, o)
+	options.AddCacheFlags(pflags, o)
+	options.AddCapabilitiesFlags(pflags, o)
+	options.AddSecretFlags(pflags, o)
+	return cmd
+}
+
+// An image deployed by a combination of flavor and layer options of an application version.
+type imageUsage struct {
+	Application string   `json:"application"`
+	Version     string   `json:"version"`
+	Flavor      string   `json:"flavor"`
+	Layers      []string `json:"layers,omitempty"`
+	Kind        string   `json:"kind"`
+	Name        string   `json:"name"`
+	Container   string   `json:"container"`
+	Image       string   `json:"image"`
+}
+
+type dedupedImage struct {
+	Image        string   `json:"image"`
+	Applications []string `json:"applications"`
+}
+
+func listImages(o *options.Options) error {
+	if o.ListImages.Output != outputTable && o.ListImages.Output != outputJson && o.ListImages.Output != outputList {
+		return UnknownOutputError(o.ListImages.Output)
+	}
+	specs, err := options.MustGetSpecReader(o).GetSpecs()
+	if err != nil {
+		return err
+	}
+	specs = bundle.SelectSpecs(specs, o.ListImages.ApplicationName, o.ListImages.Version)
+
+	secrets, err := options.GetSecretResolver(o)
+	if err != nil {
+		return err
+	}
+	renderer := options.GetManifestRenderer(o)
+	var usages []imageUsage
+	rendered, failed := 0, 0
+	for _, spec := range specs {
+		for _, version := range spec.GetVersions() {
+			for _, flavor := range version.GetFlavors() {
+				for _, layers := range render.GetLayerCombinations(flavor) {
+					combinationUsages, err := renderCombination(o, renderer, secrets, spec, version, flavor, layers)
+					if err != nil {
+						// Keep going, so that one broken or incomplete combination does not hide the images of the others.
+						fmt.Fprintf(os.Stderr, "%v\n", err)
+						failed++
+						continue
+					}
+					rendered++
+					usages = append(usages, combinationUsages...)
+				}
+			}
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%v of %v combinations could not be rendered, their images are not listed\n",
+			failed, failed+rendered)
+		if rendered == 0 {
+			return NoCombinationRenderedError
+		}
+	}
+
+	switch {
+	case o.ListImages.Output == outputList:
+		for _, image := range dedupe(usages) {
+			fmt.Println(image.Image)
+		}
+		return nil
+	case o.ListImages.Dedupe && o.ListImages.Output == outputJson:
+		return printJson(dedupe(usages))
+	case o.ListImages.Output == outputJson:
+		return printJson(usages)
+	case o.ListImages.Dedupe:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "IMAGE\tAPPLICATIONS")
+		for _, image := range dedupe(usages) {
+			fmt.Fprintf(w, "%v\t%v\n", image.Image, strings.Join(image.Applications, ", "))
+		}
+		return w.Flush()
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "APPLICATION\tVERSION\tFLAVOR\tLAYERS\tRESOURCE\tCONTAINER\tIMAGE")
+		for _, usage := range usages {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v/%v\t%v\t%v\n", usage.Application, usage.Version, usage.Flavor,
+				strings.Join(usage.Layers, ","), usage.Kind, usage.Name, usage.Container, usage.Image)
+		}
+		return w.Flush()
+	}
+}
+
+func renderCombination(o *options.Options, renderer render.ManifestRenderer, secrets util.SecretResolver, spec *v1.ApplicationSpec, version *v1.VersionedApplicationSpec, flavor *v1.Flavor, layers []render.LayerInput) ([]imageUsage, error) {
+	combination := imageUsage{
+		Application: spec.GetName(),
+		Version:     version.GetVersion(),
+		Flavor:      flavor.GetName(),
+	}
+	for _, layer := range layers {
+		combination.Layers = append(combination.Layers, layer.LayerId+"="+layer.OptionId)
+	}
+
+	params, err := render.GetDefaultParams(version, flavor, layers, secrets)
+	if err != nil {
+		return nil, FailedToRenderCombinationError(err, combination)
+	}
+	for name, value := range o.ListImages.Params {
+		params[name] = value
+	}
+	inputs := render.ValuesInputs{
+		Name:              spec.GetName(),
+		InstallNamespace:  o.InstallNamespace,
+		Flavor:            flavor,
+		Layers:            layers,
+		MeshRef:           core.ResourceRef{Name: options.ValidateDefaults.MeshName, Namespace: options.ValidateDefaults.MeshNamespace},
+		SpecDefinedValues: version.GetValuesYaml(),
+		Params:            params,
+	}
+	options.ApplyCapabilities(o, &inputs)
+	if missing := render.GetMissingRequiredParams(inputs, version); len(missing) > 0 {
+		return nil, MissingRequiredParamsError(missing, combination)
+	}
+
+	resources, err := renderer.ComputeResourcesForApplication(o.Ctx, inputs, version)
+	if err != nil {
+		return nil, FailedToRenderCombinationError(err, combination)
+	}
+	images, err := render.ExtractImages(resources)
+	if err != nil {
+		return nil, FailedToRenderCombinationError(err, combination)
+	}
+	var usages []imageUsage
+	for _, image := range images {
+		usage := combination
+		usage.Kind, usage.Name, usage.Container, usage.Image = image.Kind, image.Name, image.Container, image.Image
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// Collapses the usages into the distinct images, sorted, along with the application versions deploying each of them.
+func dedupe(usages []imageUsage) []dedupedImage {
+	applications := make(map[string]map[string]bool)
+	for _, usage := range usages {
+		if applications[usage.Image] == nil {
+			applications[usage.Image] = make(map[string]bool)
+		}
+		applications[usage.Image][usage.Application+"@"+usage.Version] = true
+	}
+	images := make([]dedupedImage, 0, len(applications))
+	for image, apps := range applications {
+		deduped := dedupedImage{Image: image}
+		for app := range apps {
+			deduped.Applications = append(deduped.Applications, app)
+		}
+		sort.Strings(deduped.Applications)
+		images = append(images, deduped)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Image < images[j].Image
+	})
+	return images
+}
+
+func printJson(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
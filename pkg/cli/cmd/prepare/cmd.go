@@ -16,20 +16,12 @@ func Cmd(o *options.Options) *cobra.Command {
 		},
 	}
 	pflags := cmd.PersistentFlags()
-	pflags.StringVarP(&o.Registry.LocalDirectory, "specs-path", "p", "",
-		"local directory to access application specs from, e.g. `./extensions/v1`")
-	pflags.StringVarP(&o.Registry.GithubRegistry.Org, "registry-org", "", options.RegistryDefaults.GithubRegistry.Org,
-		"owner of github registry")
-	pflags.StringVarP(&o.Registry.GithubRegistry.Repo, "registry-repo", "", options.RegistryDefaults.GithubRegistry.Repo,
-		"repo of github registry")
-	pflags.StringVarP(&o.Registry.GithubRegistry.Ref, "registry-ref", "", options.RegistryDefaults.GithubRegistry.Ref,
-		"ref of github registry")
-	pflags.StringVarP(&o.Registry.GithubRegistry.Directory, "registry-directory", "", options.RegistryDefaults.GithubRegistry.Directory,
-		"directory of github registry")
+	options.AddRegistryFlags(pflags, o)
 	pflags.StringVarP(&o.InstallNamespace, "namespace", "n", "default",
 		"install namespace")
 	pflags.StringVarP(&o.InstallSpecFile, "install-spec-file", "i", "",
 		"destination for application install spec")
+	options.AddSecretFlags(pflags, o)
 	return cmd
 }
 
@@ -38,8 +30,12 @@ func prepare(o *options.Options) error {
 		return err
 	}
 
+	secrets, err := options.GetSecretResolver(o)
+	if err != nil {
+		return err
+	}
 	reader := options.MustGetSpecReader(o)
-	installSpec, err := installspec.GetInstallSpec(reader, o.InstallNamespace)
+	installSpec, err := installspec.GetInstallSpec(reader, o.InstallNamespace, secrets)
 	if err != nil {
 		return err
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/solo-io/go-utils/installutils/helmchart"
 	"github.com/solo-io/service-mesh-hub/pkg/cli/installspec"
 	"github.com/solo-io/service-mesh-hub/pkg/cli/options"
+	"github.com/solo-io/service-mesh-hub/pkg/registry"
 	renderutil "github.com/solo-io/service-mesh-hub/pkg/render"
 	"github.com/solo-io/service-mesh-hub/pkg/util"
 	"github.com/spf13/cobra"
@@ -17,34 +18,32 @@ func Cmd(o *options.Options) *cobra.Command {
 		Use:   "render",
 		Short: "render a manifest",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return render(o)
+			return Render(o, options.MustGetSpecReader(o), options.GetManifestRenderer(o))
 		},
 	}
 	pflags := cmd.PersistentFlags()
 
-	pflags.StringVarP(&o.Registry.LocalDirectory, "specs-path", "p", "",
-		"local directory to access application specs from, e.g. `./extensions/v1`")
-	pflags.StringVarP(&o.Registry.GithubRegistry.Org, "registry-org", "", options.RegistryDefaults.GithubRegistry.Org,
-		"owner of github registry")
-	pflags.StringVarP(&o.Registry.GithubRegistry.Repo, "registry-repo", "", options.RegistryDefaults.GithubRegistry.Repo,
-		"repo of github registry")
-	pflags.StringVarP(&o.Registry.GithubRegistry.Ref, "registry-ref", "", options.RegistryDefaults.GithubRegistry.Ref,
-		"ref of github registry")
-	pflags.StringVarP(&o.Registry.GithubRegistry.Directory, "registry-directory", "", options.RegistryDefaults.GithubRegistry.Directory,
-		"directory of github registry")
+	options.AddRegistryFlags(pflags, o)
 	pflags.StringVarP(&o.InstallSpecFile, "install-spec-file", "i", "",
 		"optional install spec to generate manifests from")
 	pflags.StringVarP(&o.ManifestFile, "manifest-file", "m", "",
 		"optional destination for rendered manifest, otherwise print to stdout")
+	options.AddCacheFlags(pflags, o)
+	options.AddCapabilitiesFlags(pflags, o)
+	options.AddTransformFlags(pflags, o)
+	options.AddSecretFlags(pflags, o)
 	return cmd
 }
 
-func render(o *options.Options) error {
+// Renders the manifest of an application selected from the reader, or of the install spec file if one is provided.
+func Render(o *options.Options, reader registry.SpecReader, renderer renderutil.ManifestRenderer) error {
+	secrets, err := options.GetSecretResolver(o)
+	if err != nil {
+		return err
+	}
 	var installSpec *installspec.InstallSpec
-	var err error
 	if o.InstallSpecFile == "" {
-		reader := options.MustGetSpecReader(o)
-		if installSpec, err = installspec.GetInstallSpec(reader, o.InstallNamespace); err != nil {
+		if installSpec, err = installspec.GetInstallSpec(reader, o.InstallNamespace, secrets); err != nil {
 			return err
 		}
 	} else {
@@ -53,8 +52,12 @@ func render(o *options.Options) error {
 			return err
 		}
 	}
+	if err = installSpec.ResolveSecretDefaults(secrets); err != nil {
+		return err
+	}
 
-	manifest, err := renderManifest(o.Ctx, installSpec)
+	options.ApplyCapabilities(o, &installSpec.Values)
+	manifest, err := renderManifest(o.Ctx, renderer, installSpec)
 	if err != nil {
 		return err
 	}
@@ -66,8 +69,8 @@ func render(o *options.Options) error {
 	return nil
 }
 
-func renderManifest(ctx context.Context, spec *installspec.InstallSpec) (string, error) {
-	resources, err := renderutil.ComputeResourcesForApplication(ctx, spec.Values, spec.Version)
+func renderManifest(ctx context.Context, renderer renderutil.ManifestRenderer, spec *installspec.InstallSpec) (string, error) {
+	resources, err := renderer.ComputeResourcesForApplication(ctx, spec.Values, spec.Version)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,150 @@
+package render_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/render"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/installspec"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/options"
+	renderutil "github.com/solo-io/service-mesh-hub/pkg/render"
+)
+
+var _ = Describe("render", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "render-cmd-")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("prints the resources in install order", func() {
+		installSpec := &installspec.InstallSpec{
+			Values: renderutil.ValuesInputs{
+				Name:             "app",
+				InstallNamespace: "install",
+				Flavor:           &v1.Flavor{},
+			},
+			Version: &v1.VersionedApplicationSpec{
+				InstallationSpec: &v1.VersionedApplicationSpec_InlineManifests{
+					InlineManifests: &v1.InlineManifests{Yaml: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b-config
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a-config
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+`},
+				},
+			},
+		}
+		o := options.InitializeOptions(context.TODO())
+		o.InstallSpecFile = filepath.Join(dir, "install-spec.yaml")
+		o.ManifestFile = filepath.Join(dir, "manifest.yaml")
+		Expect(installSpec.Save(o.InstallSpecFile)).To(Succeed())
+
+		Expect(render.Render(o, nil, options.GetManifestRenderer(o))).To(Succeed())
+		manifest, err := ioutil.ReadFile(o.ManifestFile)
+		Expect(err).NotTo(HaveOccurred())
+
+		var kindsAndNames []string
+		for _, doc := range strings.Split(string(manifest), "\n---\n") {
+			resources, err := renderutil.YamlToResources([]byte(doc))
+			Expect(err).NotTo(HaveOccurred())
+			for _, resource := range resources {
+				kindsAndNames = append(kindsAndNames, resource.GetKind()+"/"+resource.GetName())
+			}
+		}
+		Expect(kindsAndNames).To(Equal([]string{
+			"ServiceAccount/app",
+			"ConfigMap/a-config",
+			"ConfigMap/b-config",
+			"Deployment/app",
+		}))
+	})
+
+	Context("secret params left to their default", func() {
+
+		var (
+			o           *options.Options
+			installSpec *installspec.InstallSpec
+		)
+
+		BeforeEach(func() {
+			secretFile := filepath.Join(dir, "password")
+			Expect(ioutil.WriteFile(secretFile, []byte("hunter2\n"), 0600)).To(Succeed())
+			installSpec = &installspec.InstallSpec{
+				Values: renderutil.ValuesInputs{
+					Name:             "app",
+					InstallNamespace: "install",
+					Flavor:           &v1.Flavor{},
+				},
+				Version: &v1.VersionedApplicationSpec{
+					Parameters: []*v1.Parameter{{
+						Name: "password",
+						Type: v1.ParameterType_SECRET,
+						Default: &v1.ParameterValue{Type: &v1.ParameterValue_SecretValue{
+							SecretValue: &v1.SecretValue{Type: &v1.SecretValue_FilePath{FilePath: secretFile}},
+						}},
+					}},
+					InstallationSpec: &v1.VersionedApplicationSpec_InlineManifests{
+						InlineManifests: &v1.InlineManifests{Yaml: `apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+stringData:
+  password: {{ index .Params "password" }}
+`},
+					},
+				},
+				SecretDefaults: []string{"password"},
+			}
+			o = options.InitializeOptions(context.TODO())
+			o.InstallSpecFile = filepath.Join(dir, "install-spec.yaml")
+			o.ManifestFile = filepath.Join(dir, "manifest.yaml")
+			Expect(installSpec.Save(o.InstallSpecFile)).To(Succeed())
+		})
+
+		It("keeps the file path in the install spec and resolves it when rendering", func() {
+			saved, err := ioutil.ReadFile(o.InstallSpecFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(saved)).NotTo(ContainSubstring("hunter2"))
+
+			o.Secrets.Sources = []string{options.SecretSourceFile}
+			Expect(render.Render(o, nil, options.GetManifestRenderer(o))).To(Succeed())
+			manifest, err := ioutil.ReadFile(o.ManifestFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(manifest)).To(ContainSubstring("password: hunter2"))
+		})
+
+		It("fails when the default cannot be resolved from the secret sources", func() {
+			err := render.Render(o, nil, options.GetManifestRenderer(o))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to resolve the default value of param password"))
+		})
+	})
+})
@@ -40,6 +40,9 @@ func Cmd(o *options.Options) *cobra.Command {
 		fmt.Sprintf("optional, name of the associated mesh, defaults to placeholder value: %v", options.ValidateDefaults.MeshName))
 	pflags.StringVar(&o.Validate.MeshNamespace, "mesh-namespace", options.ValidateDefaults.MeshNamespace,
 		fmt.Sprintf("optional, namespace of the associated mesh, defaults to placeholder value: %v", options.ValidateDefaults.MeshNamespace))
+	options.AddCacheFlags(pflags, o)
+	options.AddCapabilitiesFlags(pflags, o)
+	options.AddTransformFlags(pflags, o)
 	return cmd
 }
 
@@ -92,7 +95,9 @@ func validate(o *options.Options) error {
 		//SpecDefinedValues:  "",
 	}
 
-	resources, err := render.ComputeResourcesForApplication(o.Ctx, inputValues, versionContent)
+	options.ApplyCapabilities(o, &inputValues)
+
+	resources, err := options.GetManifestRenderer(o).ComputeResourcesForApplication(o.Ctx, inputValues, versionContent)
 	if err != nil {
 		return errors.Wrapf(err, "unable to compute resources on version %v", o.Validate.Version)
 	}
@@ -4,22 +4,34 @@ import (
 	"io/ioutil"
 
 	"github.com/ghodss/yaml"
+	errors "github.com/rotisserie/eris"
 	"github.com/solo-io/go-utils/protoutils"
 	v1 "github.com/solo-io/service-mesh-hub/api/v1"
 	"github.com/solo-io/service-mesh-hub/pkg/render"
+	renderutil "github.com/solo-io/service-mesh-hub/pkg/render/util"
 	"github.com/solo-io/service-mesh-hub/pkg/util"
 )
 
+var (
+	UnknownSecretDefaultError = func(name string) error {
+		return errors.Errorf("param %v has no default value to resolve", name)
+	}
+)
+
 type InstallSpec struct {
 	Values  render.ValuesInputs
 	Version *v1.VersionedApplicationSpec
+	// Names of the SECRET params that take their default, a secret ref or file path declared by the version, flavor or
+	// layer option. They are resolved when rendering so that the spec never holds their values.
+	SecretDefaults []string
 }
 
 // Workaround for being unable to marshal/unmarshal oneofs on proto messages nested in standard structs.
 // Contains human-readable yaml strings for each field in the values and versioned spec structs.
 type installSpecYaml struct {
-	Values  string
-	Version string
+	Values         string
+	Version        string
+	SecretDefaults []string `json:",omitempty"`
 }
 
 func (i *InstallSpec) Load(filename string) error {
@@ -48,6 +60,7 @@ func (i *InstallSpec) Load(filename string) error {
 
 	i.Values = *values
 	i.Version = version
+	i.SecretDefaults = spec.SecretDefaults
 
 	return nil
 }
@@ -71,6 +84,7 @@ func (i *InstallSpec) Save(filename string) error {
 	persistedSpec := &installSpecYaml{}
 	persistedSpec.Version = string(versionBytes)
 	persistedSpec.Values = string(valuesBytes)
+	persistedSpec.SecretDefaults = i.SecretDefaults
 
 	bytes, err := yaml.Marshal(persistedSpec)
 	if err != nil {
@@ -79,3 +93,40 @@ func (i *InstallSpec) Save(filename string) error {
 
 	return util.SaveFile(filename, string(bytes))
 }
+
+// Sets the SecretDefaults params to their resolved default values, to render the spec. Saving the spec afterwards would
+// store them in plain text.
+func (i *InstallSpec) ResolveSecretDefaults(secrets renderutil.SecretResolver) error {
+	if len(i.SecretDefaults) == 0 {
+		return nil
+	}
+	params := append(append([]*v1.Parameter{}, i.Version.GetParameters()...), i.Values.Flavor.GetParameters()...)
+	for _, layer := range i.Values.Layers {
+		option, err := render.GetLayerOptionFromFlavor(layer.LayerId, layer.OptionId, i.Values.Flavor)
+		if err != nil {
+			return err
+		}
+		params = append(params, option.GetParameters()...)
+	}
+	// Like the renderer, later declarations take precedence.
+	defaults := make(map[string]*v1.ParameterValue, len(params))
+	for _, param := range params {
+		defaults[param.GetName()] = param.GetDefault()
+	}
+
+	if i.Values.Params == nil {
+		i.Values.Params = make(map[string]string, len(i.SecretDefaults))
+	}
+	for _, name := range i.SecretDefaults {
+		if defaults[name] == nil {
+			return UnknownSecretDefaultError(name)
+		}
+		value, err := renderutil.ParamValueToString(defaults[name], secrets.ResolveSecret)
+		if err != nil {
+			return FailedToResolveParamDefaultError(err, name)
+		}
+		i.Values.Params[name] = value
+		i.Values.SecretParams = append(i.Values.SecretParams, name)
+	}
+	return nil
+}
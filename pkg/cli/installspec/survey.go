@@ -3,6 +3,7 @@ package installspec
 import (
 	"fmt"
 
+	errors "github.com/rotisserie/eris"
 	v1 "github.com/solo-io/service-mesh-hub/api/v1"
 	"github.com/solo-io/service-mesh-hub/pkg/registry"
 	"github.com/solo-io/service-mesh-hub/pkg/render"
@@ -10,7 +11,16 @@ import (
 	"gopkg.in/AlecAivazis/survey.v1"
 )
 
-func GetInstallSpec(reader registry.SpecReader, installNamespace string) (*InstallSpec, error) {
+var (
+	FailedToResolveParamDefaultError = func(err error, name string) error {
+		return errors.Wrapf(err, "failed to resolve the default value of param %v, check --secret-sources", name)
+	}
+)
+
+// Prompts for the application, version, flavor, layers and params to install. The defaults of SECRET params are resolved
+// with the resolver to check them, but only the names of the params that take them are kept, see
+// InstallSpec.SecretDefaults.
+func GetInstallSpec(reader registry.SpecReader, installNamespace string, secrets util.SecretResolver) (*InstallSpec, error) {
 	spec, err := selectApplication(reader)
 	if err != nil {
 		return nil, err
@@ -19,40 +29,44 @@ func GetInstallSpec(reader registry.SpecReader, installNamespace string) (*Insta
 	if err != nil {
 		return nil, err
 	}
-	values, err := GetValuesInputs(spec, version, installNamespace)
+	values, secretDefaults, err := GetValuesInputs(spec, version, installNamespace, secrets)
 	if err != nil {
 		return nil, err
 	}
 
 	return &InstallSpec{
-		Values:  *values,
-		Version: version,
+		Values:         *values,
+		Version:        version,
+		SecretDefaults: secretDefaults,
 	}, nil
 }
 
-func GetValuesInputs(spec *v1.ApplicationSpec, version *v1.VersionedApplicationSpec, installNamespace string) (*render.ValuesInputs, error) {
+// Returns the inputs, and the names of the SECRET params left to their default. Their values are not in the params of
+// the inputs.
+func GetValuesInputs(spec *v1.ApplicationSpec, version *v1.VersionedApplicationSpec, installNamespace string, secrets util.SecretResolver) (*render.ValuesInputs, []string, error) {
 	values := render.ValuesInputs{
 		Name:              spec.Name,
 		InstallNamespace:  installNamespace,
 		SpecDefinedValues: version.ValuesYaml,
 		Params:            make(map[string]string),
 	}
+	var secretDefaults []string
 
-	if err := selectParams(version.GetParameters(), values.Params); err != nil {
-		return nil, err
+	if err := selectParams(version.GetParameters(), values.Params, &secretDefaults, secrets); err != nil {
+		return nil, nil, err
 	}
 
 	flavor, err := selectFlavor(version)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	values.Flavor = flavor
-	if err = selectParams(flavor.GetParameters(), values.Params); err != nil {
-		return nil, err
+	if err = selectParams(flavor.GetParameters(), values.Params, &secretDefaults, secrets); err != nil {
+		return nil, nil, err
 	}
 
 	if values.Layers, err = selectLayerInputList(flavor); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, layer := range flavor.GetCustomizationLayers() {
@@ -60,15 +74,15 @@ func GetValuesInputs(spec *v1.ApplicationSpec, version *v1.VersionedApplicationS
 			if layer.Id == layerInput.LayerId {
 				for _, option := range layer.Options {
 					if option.Id == layerInput.OptionId {
-						if err := selectParams(option.GetParameters(), values.Params); err != nil {
-							return nil, err
+						if err := selectParams(option.GetParameters(), values.Params, &secretDefaults, secrets); err != nil {
+							return nil, nil, err
 						}
 					}
 				}
 			}
 		}
 	}
-	return &values, nil
+	return &values, secretDefaults, nil
 }
 
 func selectApplication(reader registry.SpecReader) (*v1.ApplicationSpec, error) {
@@ -185,8 +199,20 @@ func selectLayerOption(layer *v1.Layer) (*v1.LayerOption, error) {
 	return displayNameToLayerOption[option], nil
 }
 
-func selectParams(specs []*v1.Parameter, dest map[string]string) error {
+func selectParams(specs []*v1.Parameter, dest map[string]string, secretDefaults *[]string, secrets util.SecretResolver) error {
 	for _, spec := range specs {
+		if isSecretParam(spec) {
+			val, useDefault, err := selectSecretParam(spec, secrets)
+			if err != nil {
+				return err
+			}
+			if useDefault {
+				*secretDefaults = append(*secretDefaults, spec.Name)
+			} else {
+				dest[spec.Name] = val
+			}
+			continue
+		}
 		val, err := selectParam(spec)
 		if err != nil {
 			return err
@@ -196,16 +222,102 @@ func selectParams(specs []*v1.Parameter, dest map[string]string) error {
 	return nil
 }
 
+func isSecretParam(spec *v1.Parameter) bool {
+	_, secretDefault := spec.GetDefault().GetType().(*v1.ParameterValue_SecretValue)
+	return spec.Type == v1.ParameterType_SECRET || secretDefault
+}
+
 func selectParam(spec *v1.Parameter) (string, error) {
 	d, err := util.ParamValueToString(spec.Default, util.PlainTextSecretGetter)
 	if err != nil {
-		return "", err
+		return "", FailedToResolveParamDefaultError(err, spec.Name)
+	}
+	message := fmt.Sprintf("[%s] %s", spec.Description, spec.Name)
+	if len(spec.AllowedValues) > 0 {
+		return selectAllowedParamValue(spec, d, message)
 	}
 	prompt := &survey.Input{
 		Default: d,
-		Message: fmt.Sprintf("[%s] %s", spec.Description, spec.Name),
+		Message: message,
 	}
 	input := ""
-	err = survey.AskOne(prompt, &input, nil)
+	err = survey.AskOne(prompt, &input, paramValidator(spec))
 	return input, err
 }
+
+// Prompts for the value of a SECRET param without echoing it. The default is resolved to check that it can be, but it
+// is neither shown nor returned: an empty answer selects it, so that the install spec keeps its secret ref or file path.
+func selectSecretParam(spec *v1.Parameter, secrets util.SecretResolver) (string, bool, error) {
+	message := fmt.Sprintf("[%s] %s", spec.Description, spec.Name)
+	hasDefault := spec.GetDefault() != nil
+	if hasDefault {
+		if _, err := util.ParamValueToString(spec.Default, secrets.ResolveSecret); err != nil {
+			return "", false, FailedToResolveParamDefaultError(err, spec.Name)
+		}
+		message += " (leave empty for the default)"
+	}
+	prompt := &survey.Password{
+		Message: message,
+	}
+	input := ""
+	if err := survey.AskOne(prompt, &input, secretParamValidator(spec, hasDefault)); err != nil {
+		return "", false, err
+	}
+	if input == "" && hasDefault {
+		return "", true, nil
+	}
+	return input, false, nil
+}
+
+func selectAllowedParamValue(spec *v1.Parameter, defaultValue, message string) (string, error) {
+	options := append([]string{}, spec.AllowedValues...)
+	var v survey.Validator
+	if spec.Required {
+		v = survey.Required
+	} else {
+		options = append(options, "< skip >")
+	}
+
+	prompt := &survey.Select{
+		Options:  options,
+		Message:  message,
+		PageSize: 10,
+	}
+	// The select prompt fails on defaults that are not one of its options.
+	for _, option := range spec.AllowedValues {
+		if option == defaultValue {
+			prompt.Default = defaultValue
+		}
+	}
+
+	value := ""
+	if err := survey.AskOne(prompt, &value, v); err != nil {
+		return "", err
+	}
+	if value == "< skip >" {
+		return "", nil
+	}
+	return value, nil
+}
+
+// Rejects values that do not match the type and constraints of the param. Templates are validated by the renderer once
+// rendered.
+func paramValidator(spec *v1.Parameter) survey.Validator {
+	return func(ans interface{}) error {
+		value, _ := ans.(string)
+		if render.IsParamTemplate(value) {
+			return nil
+		}
+		return render.ValidateParamValue(spec, value)
+	}
+}
+
+func secretParamValidator(spec *v1.Parameter, hasDefault bool) survey.Validator {
+	validate := paramValidator(spec)
+	return func(ans interface{}) error {
+		if value, _ := ans.(string); value == "" && spec.Required && !hasDefault {
+			return survey.Required(ans)
+		}
+		return validate(ans)
+	}
+}
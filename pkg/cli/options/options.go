@@ -2,17 +2,27 @@ package options
 
 import (
 	"context"
+	"time"
 
 	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/cache"
 )
 
 type Options struct {
 	Ctx              context.Context
 	Validate         Validate
 	Registry         Registry
+	Cache            Cache
+	Bundle           Bundle
 	InstallNamespace string
 	InstallSpecFile  string
 	ManifestFile     string
+	Capabilities     Capabilities
+	Transform        Transform
+	Images           Images
+	ListImages       ListImages
+	Secrets          Secrets
+	ExplainValues    ExplainValues
 }
 
 type Validate struct {
@@ -42,6 +52,7 @@ var ValidateDefaults = Validate{
 type Registry struct {
 	LocalDirectory string
 	GithubRegistry v1.GithubRepositoryLocation
+	GitRegistry    v1.GitRepositoryLocation
 }
 
 var RegistryDefaults = Registry{
@@ -51,12 +62,81 @@ var RegistryDefaults = Registry{
 		Ref:       "better-layering-aug",
 		Directory: "meshes/v1",
 	},
+	GitRegistry: v1.GitRepositoryLocation{
+		Ref:       "master",
+		Directory: "meshes/v1",
+	},
+}
+
+type Cache struct {
+	Directory string
+	Disabled  bool
+	MaxSizeMb int64
+	MaxAge    time.Duration
+	Warm      CacheWarm
+}
+
+type CacheWarm struct {
+	ApplicationName string
+	Version         string
+}
+
+var CacheDefaults = Cache{
+	MaxSizeMb: cache.DefaultMaxSize >> 20,
+	MaxAge:    cache.DefaultMaxAge,
+}
+
+// Describes the target cluster when rendering charts.
+type Capabilities struct {
+	KubeVersion string
+	ApiVersions []string
+}
+
+// Customizations applied to every rendered resource.
+type Transform struct {
+	CommonLabels      map[string]string
+	CommonAnnotations map[string]string
+	NamePrefix        string
+	Provenance        bool
+}
+
+// Rewrites of the container images of rendered workloads.
+type Images struct {
+	Registry string
+	Mappings map[string]string
+}
+
+type ListImages struct {
+	ApplicationName string
+	Version         string
+	Output          string
+	Dedupe          bool
+	Params          map[string]string
+}
+
+// Where to resolve the values of SECRET params from, besides plain text.
+type Secrets struct {
+	Sources      []string
+	EnvPrefix    string
+	SnapshotFile string
+	Kubeconfig   string
+}
+
+type ExplainValues struct {
+	Output string
+}
+
+type Bundle struct {
+	File            string
+	ApplicationName string
+	Version         string
 }
 
 func InitializeOptions(ctx context.Context) *Options {
 	opts := &Options{
 		Ctx:      ctx,
 		Validate: ValidateDefaults,
+		Cache:    CacheDefaults,
 	}
 	return opts
 }
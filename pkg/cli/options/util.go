@@ -1,15 +1,27 @@
 package options
 
 import (
+	"fmt"
 	"path/filepath"
 
+	errors "github.com/rotisserie/eris"
 	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/kubeutils"
+	"github.com/solo-io/service-mesh-hub/pkg/cache"
 	"github.com/solo-io/service-mesh-hub/pkg/registry"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/service-mesh-hub/pkg/render/util"
+	"github.com/solo-io/service-mesh-hub/pkg/render/validation"
+	"github.com/spf13/pflag"
 	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
 )
 
 func MustGetSpecReader(o *Options) registry.SpecReader {
 	if o.Registry.LocalDirectory == "" {
+		if o.Registry.GitRegistry.Url != "" {
+			return registry.NewGitSpecReader(o.Ctx, o.Registry.GitRegistry)
+		}
 		return registry.NewGithubSpecReader(o.Ctx, o.Registry.GithubRegistry)
 	}
 
@@ -20,3 +32,194 @@ func MustGetSpecReader(o *Options) registry.SpecReader {
 
 	return registry.NewLocalSpecReader(o.Ctx, absPath)
 }
+
+func GetArtifactCache(o *Options) (*cache.ArtifactCache, error) {
+	dir := o.Cache.Directory
+	if dir == "" {
+		defaultDir, err := cache.DefaultDirectory()
+		if err != nil {
+			return nil, err
+		}
+		dir = defaultDir
+	}
+	artifactCache := cache.NewArtifactCache(dir)
+	artifactCache.MaxSize = o.Cache.MaxSizeMb << 20
+	artifactCache.MaxAge = o.Cache.MaxAge
+	return artifactCache, nil
+}
+
+// Returns a fetcher backed by the artifact cache, unless caching is disabled.
+func GetArtifactFetcher(o *Options) render.ArtifactFetcher {
+	if o.Cache.Disabled {
+		return render.NewRemoteArtifactFetcher()
+	}
+	artifactCache, err := GetArtifactCache(o)
+	if err != nil {
+		contextutils.LoggerFrom(o.Ctx).Warnw("Failed to locate artifact cache, fetching without it", zap.Error(err))
+		return render.NewRemoteArtifactFetcher()
+	}
+	return render.NewCachingArtifactFetcher(artifactCache, render.NewRemoteArtifactFetcher())
+}
+
+func GetManifestRenderer(o *Options) render.ManifestRenderer {
+	return GetManifestRendererWithFetcher(o, GetArtifactFetcher(o))
+}
+
+// Returns a renderer that retrieves artifacts with the fetcher, customizing the rendered resources as requested on the
+// command line. Resources are sorted in install order, so that manifests are stable across renders and can be applied
+// in order.
+func GetManifestRendererWithFetcher(o *Options, fetcher render.ArtifactFetcher) render.ManifestRenderer {
+	opts := []render.Option{
+		render.WithArtifactFetcher(fetcher),
+		render.WithTransformers(GetTransformers(o)...),
+		render.WithInstallOrder(),
+	}
+	if o.Transform.Provenance {
+		opts = append(opts, render.WithProvenance())
+	}
+	return render.NewManifestRenderer(validation.NoopValidateResources, opts...)
+}
+
+// Returns the transformers requested on the command line, in the order they are applied.
+func GetTransformers(o *Options) []render.Transformer {
+	var transformers []render.Transformer
+	if o.Transform.NamePrefix != "" {
+		transformers = append(transformers, render.NamePrefix(o.Transform.NamePrefix))
+	}
+	if len(o.Transform.CommonLabels) > 0 {
+		transformers = append(transformers, render.CommonLabels(o.Transform.CommonLabels))
+	}
+	if len(o.Transform.CommonAnnotations) > 0 {
+		transformers = append(transformers, render.CommonAnnotations(o.Transform.CommonAnnotations))
+	}
+	if o.Images.Registry != "" || len(o.Images.Mappings) > 0 {
+		transformers = append(transformers, &render.ImageRewriter{
+			Registry: o.Images.Registry,
+			Mappings: o.Images.Mappings,
+		})
+	}
+	return transformers
+}
+
+const (
+	SecretSourceFile = "file"
+	SecretSourceEnv  = "env"
+	SecretSourceKube = "kube"
+)
+
+var (
+	UnknownSecretSourceError = func(source string) error {
+		return errors.Errorf("unknown secret source %v, expected one of %v, %v, %v",
+			source, SecretSourceFile, SecretSourceEnv, SecretSourceKube)
+	}
+
+	FailedToCreateKubeClientError = func(err error) error {
+		return errors.Wrapf(err, "failed to create kubernetes client to resolve secrets")
+	}
+)
+
+// Returns a resolver for the values of SECRET params, trying the sources requested on the command line in order.
+// Kubernetes secrets are read from the snapshot file if one is provided, otherwise from the cluster.
+func GetSecretResolver(o *Options) (util.SecretResolver, error) {
+	var resolvers util.SecretResolverChain
+	for _, source := range o.Secrets.Sources {
+		switch source {
+		case SecretSourceFile:
+			resolvers = append(resolvers, util.FileSecretResolver{})
+		case SecretSourceEnv:
+			resolvers = append(resolvers, util.EnvSecretResolver{Prefix: o.Secrets.EnvPrefix})
+		case SecretSourceKube:
+			client, err := getSecretClient(o)
+			if err != nil {
+				return nil, err
+			}
+			resolvers = append(resolvers, util.KubeSecretResolver{Secrets: client})
+		default:
+			return nil, UnknownSecretSourceError(source)
+		}
+	}
+	return resolvers, nil
+}
+
+func getSecretClient(o *Options) (util.SecretClient, error) {
+	if o.Secrets.SnapshotFile != "" {
+		return util.LoadSecretSnapshot(o.Secrets.SnapshotFile)
+	}
+	cfg, err := kubeutils.GetConfig("", o.Secrets.Kubeconfig)
+	if err != nil {
+		return nil, FailedToCreateKubeClientError(err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, FailedToCreateKubeClientError(err)
+	}
+	return util.NewClientsetSecretClient(clientset), nil
+}
+
+func AddCacheFlags(pflags *pflag.FlagSet, o *Options) {
+	pflags.StringVar(&o.Cache.Directory, "cache-dir", "",
+		"directory in which to cache downloaded charts and archives, defaults to ~/.hubctl/cache")
+	pflags.BoolVar(&o.Cache.Disabled, "no-cache", false,
+		"if set, always download charts and archives instead of using the cache")
+}
+
+func AddRegistryFlags(pflags *pflag.FlagSet, o *Options) {
+	pflags.StringVarP(&o.Registry.LocalDirectory, "specs-path", "p", "",
+		"local directory to access application specs from, e.g. `./extensions/v1`")
+	pflags.StringVarP(&o.Registry.GithubRegistry.Org, "registry-org", "", RegistryDefaults.GithubRegistry.Org,
+		"owner of github registry")
+	pflags.StringVarP(&o.Registry.GithubRegistry.Repo, "registry-repo", "", RegistryDefaults.GithubRegistry.Repo,
+		"repo of github registry")
+	pflags.StringVarP(&o.Registry.GithubRegistry.Ref, "registry-ref", "", RegistryDefaults.GithubRegistry.Ref,
+		"ref of github registry")
+	pflags.StringVarP(&o.Registry.GithubRegistry.Directory, "registry-directory", "", RegistryDefaults.GithubRegistry.Directory,
+		"directory of github registry")
+	pflags.StringVar(&o.Registry.GitRegistry.Url, "registry-git-url", "",
+		"url of a git repository to use as the registry instead of github, e.g. `https://git.example.com/hub.git`")
+	pflags.StringVar(&o.Registry.GitRegistry.Ref, "registry-git-ref", RegistryDefaults.GitRegistry.Ref,
+		"ref of git registry")
+	pflags.StringVar(&o.Registry.GitRegistry.Directory, "registry-git-directory", RegistryDefaults.GitRegistry.Directory,
+		"directory of git registry")
+}
+
+func AddCapabilitiesFlags(pflags *pflag.FlagSet, o *Options) {
+	pflags.StringVar(&o.Capabilities.KubeVersion, "kube-version", "",
+		"kubernetes version of the target cluster used when rendering charts, e.g. `v1.17.2`")
+	pflags.StringSliceVar(&o.Capabilities.ApiVersions, "api-versions", nil,
+		"api versions available on the target cluster in addition to the defaults used when rendering charts, e.g. `monitoring.coreos.com/v1`")
+}
+
+func AddTransformFlags(pflags *pflag.FlagSet, o *Options) {
+	pflags.StringToStringVar(&o.Transform.CommonLabels, "common-labels", nil,
+		"labels to add to every rendered resource, e.g. `team=mesh,env=prod`")
+	pflags.StringToStringVar(&o.Transform.CommonAnnotations, "common-annotations", nil,
+		"annotations to add to every rendered resource")
+	pflags.StringVar(&o.Transform.NamePrefix, "name-prefix", "",
+		"prefix to add to the names of the rendered resources and to the references to them, except for namespaces and CRDs")
+	pflags.BoolVar(&o.Transform.Provenance, "provenance", false,
+		"if set, annotate every rendered resource with the application, version, flavor, layers, source and values it was rendered from")
+	pflags.StringVar(&o.Images.Registry, "image-registry", "",
+		"registry to pull every container image from, keeping its repository path, e.g. `mirror.example.com`")
+	pflags.StringToStringVar(&o.Images.Mappings, "image-map", nil,
+		"container images to replace, applied before --image-registry, e.g. `istio/proxyv2=mirror.example.com/proxyv2`")
+}
+
+func AddSecretFlags(pflags *pflag.FlagSet, o *Options) {
+	pflags.StringSliceVar(&o.Secrets.Sources, "secret-sources", nil,
+		fmt.Sprintf("where to resolve the values of secret params from besides plain text, tried in order: %v for file paths, "+
+			"%v or %v for kubernetes secret refs, e.g. `env,kube`", SecretSourceFile, SecretSourceEnv, SecretSourceKube))
+	pflags.StringVar(&o.Secrets.EnvPrefix, "secret-env-prefix", util.DefaultSecretEnvPrefix,
+		"prefix of the environment variables holding secrets, followed by the namespace, name and key of the secret ref")
+	pflags.StringVar(&o.Secrets.SnapshotFile, "secrets-snapshot", "",
+		"yaml file to read kubernetes secrets from instead of the cluster, e.g. the output of kubectl get secrets -o yaml")
+	pflags.StringVar(&o.Secrets.Kubeconfig, "kubeconfig", "",
+		"kubeconfig of the cluster to read kubernetes secrets from, defaults to $KUBECONFIG or ~/.kube/config")
+}
+
+// Overrides the target cluster described by the inputs with the one given on the command line, if any.
+func ApplyCapabilities(o *Options, inputs *render.ValuesInputs) {
+	if o.Capabilities.KubeVersion != "" {
+		inputs.KubeVersion = o.Capabilities.KubeVersion
+	}
+	inputs.ApiVersions = append(inputs.ApiVersions, o.Capabilities.ApiVersions...)
+}
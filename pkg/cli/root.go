@@ -4,6 +4,10 @@ import (
 	"context"
 
 	"github.com/solo-io/go-utils/clicore"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/bundle"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/cache"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/explain"
+	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/images"
 	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/prepare"
 	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/render"
 	"github.com/solo-io/service-mesh-hub/pkg/cli/cmd/validate"
@@ -32,6 +36,10 @@ func App(ctx context.Context, version string) *cobra.Command {
 	}
 	o := options.InitializeOptions(ctx)
 	cmd.AddCommand(
+		bundle.Cmd(o),
+		cache.Cmd(o),
+		explain.Cmd(o),
+		images.Cmd(o),
 		prepare.Cmd(o),
 		render.Cmd(o),
 		validate.Cmd(o))
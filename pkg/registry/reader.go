@@ -21,6 +21,7 @@ import (
 	"github.com/solo-io/go-utils/contextutils"
 	"github.com/solo-io/go-utils/protoutils"
 	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
 	"go.uber.org/zap"
 )
 
@@ -47,6 +48,10 @@ var (
 		return errors.Wrap(err, "Failed to get application specs from github")
 	}
 
+	FailedToGetSpecsFromGitError = func(err error) error {
+		return errors.Wrap(err, "Failed to get application specs from git")
+	}
+
 	FailedToGetLocalSpecsError = func(err error) error {
 		return errors.Wrap(err, "Failed to get local application specs")
 	}
@@ -144,7 +149,7 @@ func (r *GithubSpecReader) GetSpecs() ([]*v1.ApplicationSpec, error) {
 		return nil, wrapped
 	}
 
-	return getSpecsFromDirectory(r.ctx, fs, subdirs, specParent)
+	return getSpecsFromDirectory(r.ctx, fs, subdirs, specParent, false)
 }
 
 var _ SpecReader = &GithubSpecReader{}
@@ -159,6 +164,53 @@ func NewGithubSpecReader(ctx context.Context, location v1.GithubRepositoryLocati
 	}
 }
 
+type GitSpecReader struct {
+	ctx      context.Context
+	location v1.GitRepositoryLocation
+}
+
+func (r *GitSpecReader) GetSpecs() ([]*v1.ApplicationSpec, error) {
+	contextutils.LoggerFrom(r.ctx).Infow("getting all application specs from git directory",
+		zap.Any("location", r.location))
+
+	content, err := render.FetchGitRepositoryArchive(r.ctx, &r.location)
+	if err != nil {
+		wrapped := FailedToGetSpecsFromGitError(err)
+		contextutils.LoggerFrom(r.ctx).Errorw(wrapped.Error(), zap.Error(err))
+		return nil, wrapped
+	}
+	fs := afero.NewMemMapFs()
+	codeDir, err := render.UntarRepositoryArchive(fs, content)
+	if err != nil {
+		wrapped := FailedToGetSpecsFromGitError(err)
+		contextutils.LoggerFrom(r.ctx).Errorw(wrapped.Error(), zap.Error(err))
+		return nil, wrapped
+	}
+
+	specParent := filepath.Join(codeDir, r.location.Directory)
+	subdirs, err := afero.ReadDir(fs, specParent)
+	if err != nil {
+		wrapped := FailedToGetSpecsFromGitError(err)
+		contextutils.LoggerFrom(r.ctx).Errorw(wrapped.Error(), zap.Error(err))
+		return nil, wrapped
+	}
+
+	return getSpecsFromDirectory(r.ctx, fs, subdirs, specParent, false)
+}
+
+var _ SpecReader = &GitSpecReader{}
+
+// Reads specs from a directory of a git repository hosted on any git server.
+func NewGitSpecReader(ctx context.Context, location v1.GitRepositoryLocation) *GitSpecReader {
+	contextutils.LoggerFrom(ctx).Infow("Initializing reader for git spec registry",
+		zap.Any("location", location))
+
+	return &GitSpecReader{
+		ctx:      ctx,
+		location: location,
+	}
+}
+
 type LocalSpecReader struct {
 	ctx  context.Context
 	path string
@@ -173,7 +225,7 @@ func (r *LocalSpecReader) GetSpecs() ([]*v1.ApplicationSpec, error) {
 		return nil, wrapped
 	}
 
-	return getSpecsFromDirectory(r.ctx, fs, subdirs, r.path)
+	return getSpecsFromDirectory(r.ctx, fs, subdirs, r.path, true)
 }
 
 var _ SpecReader = &LocalSpecReader{}
@@ -185,7 +237,8 @@ func NewLocalSpecReader(ctx context.Context, path string) *LocalSpecReader {
 	}
 }
 
-func getSpecsFromDirectory(ctx context.Context, fs afero.Fs, subdirs []os.FileInfo, specParent string) ([]*v1.ApplicationSpec, error) {
+// If resolveLocalPaths is set, relative local installation sources are resolved against the directory of their spec.
+func getSpecsFromDirectory(ctx context.Context, fs afero.Fs, subdirs []os.FileInfo, specParent string, resolveLocalPaths bool) ([]*v1.ApplicationSpec, error) {
 	// Create an application spec for every subdirectory
 	var specs []*v1.ApplicationSpec
 	for _, subdir := range subdirs {
@@ -201,6 +254,12 @@ func getSpecsFromDirectory(ctx context.Context, fs afero.Fs, subdirs []os.FileIn
 			contextutils.LoggerFrom(ctx).Errorw("Failed to unmarshal spec file", zap.Error(err), zap.String("file", specPath))
 			continue
 		}
+		if resolveLocalPaths {
+			if err := ResolveLocalPaths(spec, filepath.Join(specParent, subdir.Name())); err != nil {
+				contextutils.LoggerFrom(ctx).Errorw("Failed to resolve local paths", zap.Error(err), zap.String("file", specPath))
+				continue
+			}
+		}
 
 		// If provided, render description.md to html and override the inline long description.
 		// Else, render the long description to html as if it were markdown to simplify rendering on web.
@@ -231,3 +290,28 @@ func getSpecsFromDirectory(ctx context.Context, fs afero.Fs, subdirs []os.FileIn
 
 	return specs, nil
 }
+
+// Makes the relative paths of the spec's local installation sources absolute, resolving them against specDir.
+func ResolveLocalPaths(spec *v1.ApplicationSpec, specDir string) error {
+	absSpecDir, err := filepath.Abs(specDir)
+	if err != nil {
+		return err
+	}
+	for _, version := range spec.GetVersions() {
+		if version.GetInstallationSpec() == nil {
+			continue
+		}
+		err := render.VisitArtifacts(version, render.ArtifactVisitor{
+			Local: func(location *v1.LocalLocation) error {
+				if !filepath.IsAbs(location.Path) {
+					location.Path = filepath.Join(absSpecDir, location.Path)
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
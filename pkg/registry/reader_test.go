@@ -2,6 +2,10 @@ package registry_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 
 	v1 "github.com/solo-io/service-mesh-hub/api/v1"
 	"github.com/solo-io/service-mesh-hub/pkg/registry"
@@ -89,3 +93,84 @@ var _ = Describe("Reader", func() {
 		})
 	})
 })
+
+var _ = Describe("ResolveLocalPaths", func() {
+	It("resolves relative local paths against the spec directory", func() {
+		spec := &v1.ApplicationSpec{
+			Versions: []*v1.VersionedApplicationSpec{
+				{
+					InstallationSpec: &v1.VersionedApplicationSpec_LocalChart{
+						LocalChart: &v1.LocalLocation{Path: "chart"},
+					},
+				},
+				{
+					InstallationSpec: &v1.VersionedApplicationSpec_LocalManifests{
+						LocalManifests: &v1.LocalLocation{Path: "/abs/manifests"},
+					},
+				},
+			},
+		}
+		Expect(registry.ResolveLocalPaths(spec, "/specs/app")).To(Succeed())
+		Expect(spec.Versions[0].GetLocalChart().Path).To(Equal("/specs/app/chart"))
+		Expect(spec.Versions[1].GetLocalManifests().Path).To(Equal("/abs/manifests"))
+	})
+})
+
+var _ = Describe("GitSpecReader", func() {
+
+	var dir string
+
+	git := func(dir string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "git-registry-")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reads specs from a local bare repository", func() {
+		workDir := filepath.Join(dir, "work")
+		specDir := filepath.Join(workDir, "extensions", "v1", "app")
+		Expect(os.MkdirAll(specDir, 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte("name: app\n"), 0644)).To(Succeed())
+		git(workDir, "init", "--quiet")
+		git(workDir, "add", ".")
+		git(workDir, "commit", "--quiet", "-m", "add spec")
+		git(dir, "clone", "--bare", "--quiet", workDir, filepath.Join(dir, "registry.git"))
+
+		reader := registry.NewGitSpecReader(context.TODO(), v1.GitRepositoryLocation{
+			Url:       filepath.Join(dir, "registry.git"),
+			Directory: "extensions/v1",
+		})
+		specs, err := reader.GetSpecs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(specs).To(HaveLen(1))
+		Expect(specs[0].Name).To(Equal("app"))
+	})
+
+	It("errors with a bad url", func() {
+		reader := registry.NewGitSpecReader(context.TODO(), v1.GitRepositoryLocation{Url: filepath.Join(dir, "missing.git")})
+		_, err := reader.GetSpecs()
+		Expect(err).To(HaveOccurred())
+		expectedErr := registry.FailedToGetSpecsFromGitError(errors.Errorf(""))
+		Expect(err.Error()).To(ContainSubstring(expectedErr.Error()))
+	})
+
+	It("does not pass urls to git as options", func() {
+		marker := filepath.Join(dir, "marker")
+		reader := registry.NewGitSpecReader(context.TODO(), v1.GitRepositoryLocation{Url: "--upload-pack=touch " + marker})
+		_, err := reader.GetSpecs()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid git url"))
+		Expect(marker).NotTo(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,262 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/installutils"
+	"github.com/solo-io/go-utils/installutils/helmchart"
+	"github.com/solo-io/go-utils/installutils/helmignore"
+	"github.com/solo-io/go-utils/tarutils"
+	"github.com/spf13/afero"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+const archiveFilename = "archive.tgz"
+
+var (
+	UnexpectedArchiveLayoutError = func(found int) error {
+		return errors.Errorf("expected a single directory at the root of the archive, found %d entries", found)
+	}
+
+	InvalidKubeVersionError = func(err error, kubeVersion string) error {
+		return errors.Wrapf(err, "invalid kubernetes version %v", kubeVersion)
+	}
+
+	IncompatibleKubeVersionError = func(chartName, constraint, kubeVersion string) error {
+		return errors.Errorf("chart %v requires kubernetes %v, which is incompatible with %v", chartName, constraint, kubeVersion)
+	}
+)
+
+// Loads a helm chart from the content of a chart tgz.
+func LoadChartArchive(content []byte) (*chart.Chart, error) {
+	c, err := loader.LoadArchive(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading chart")
+	}
+	return c, nil
+}
+
+// Loads the helm chart found at chartDirectory inside of a gzipped tarball of a repository.
+func LoadChartFromRepositoryArchive(content []byte, chartDirectory string) (*chart.Chart, error) {
+	fs := afero.NewMemMapFs()
+	repoDir, err := UntarRepositoryArchive(fs, content)
+	if err != nil {
+		return nil, err
+	}
+	return LoadChartDirectory(fs, filepath.Join(repoDir, chartDirectory))
+}
+
+// Loads the helm chart at the root of a gzipped tarball of a chart directory.
+func LoadChartFromDirectoryArchive(content []byte) (*chart.Chart, error) {
+	fs := afero.NewMemMapFs()
+	dir, err := untarArchive(fs, content)
+	if err != nil {
+		return nil, err
+	}
+	return LoadChartDirectory(fs, dir)
+}
+
+// Loads a helm chart from a directory, honoring the chart's .helmignore file.
+func LoadChartDirectory(fs afero.Fs, chartDir string) (*chart.Chart, error) {
+	rules, err := getHelmIgnoreRules(fs, chartDir)
+	if err != nil {
+		return nil, err
+	}
+	chartDir = filepath.Clean(chartDir) + string(filepath.Separator)
+
+	var files []*loader.BufferedFile
+	walk := func(name string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		n := filepath.ToSlash(strings.TrimPrefix(name, chartDir))
+		if n == "" {
+			// No need to process the top level directory.
+			return nil
+		}
+		if fi.IsDir() {
+			if rules.Ignore(n, fi) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rules.Ignore(n, fi) {
+			return nil
+		}
+		data, err := afero.ReadFile(fs, name)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %s", n, err)
+		}
+		files = append(files, &loader.BufferedFile{Name: n, Data: data})
+		return nil
+	}
+	if err := afero.Walk(fs, chartDir, walk); err != nil {
+		return nil, err
+	}
+	c, err := loader.LoadFiles(files)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading chart")
+	}
+	return c, nil
+}
+
+// Returns the capabilities of the target cluster described by the inputs, based on helm's defaults.
+func GetCapabilities(inputs ValuesInputs) (*chartutil.Capabilities, error) {
+	capabilities := *chartutil.DefaultCapabilities
+	if inputs.KubeVersion != "" {
+		version, err := semver.NewVersion(inputs.KubeVersion)
+		if err != nil {
+			return nil, InvalidKubeVersionError(err, inputs.KubeVersion)
+		}
+		capabilities.KubeVersion = chartutil.KubeVersion{
+			Version: "v" + version.String(),
+			Major:   strconv.FormatUint(version.Major(), 10),
+			Minor:   strconv.FormatUint(version.Minor(), 10),
+		}
+	}
+	capabilities.APIVersions = append(append(chartutil.VersionSet{}, chartutil.DefaultVersionSet...), inputs.ApiVersions...)
+	return &capabilities, nil
+}
+
+// Renders the chart's templates with the given values into manifests, for the release and cluster described by the
+// inputs.
+func RenderChart(ctx context.Context, c *chart.Chart, values string, inputs ValuesInputs) (helmchart.Manifests, error) {
+	valuesMap, err := chartutil.ReadValues([]byte(values))
+	if err != nil {
+		return nil, err
+	}
+	capabilities, err := GetCapabilities(inputs)
+	if err != nil {
+		return nil, err
+	}
+	if constraint := c.Metadata.KubeVersion; constraint != "" && inputs.KubeVersion != "" {
+		if !chartutil.IsCompatibleRange(constraint, capabilities.KubeVersion.Version) {
+			return nil, IncompatibleKubeVersionError(c.Name(), constraint, capabilities.KubeVersion.Version)
+		}
+	}
+	renderValues, err := chartutil.ToRenderValues(c, valuesMap, chartutil.ReleaseOptions{
+		Name:      inputs.Name,
+		Namespace: inputs.InstallNamespace,
+	}, capabilities)
+	if err != nil {
+		return nil, err
+	}
+	renderedTemplates, err := engine.Render(c, renderValues)
+	if err != nil {
+		return nil, err
+	}
+	for file, manifest := range renderedTemplates {
+		if helmchart.IsEmptyManifest(manifest) {
+			contextutils.LoggerFrom(ctx).Debugf("is an empty manifest, removing %v", file)
+			delete(renderedTemplates, file)
+		}
+	}
+	return sortManifestsByKind(installutils.SplitManifests(renderedTemplates)), nil
+}
+
+// Reads the manifests at the root of a gzipped tarball, in the order of their file names.
+func GetManifestsFromArchiveContent(content []byte) (helmchart.Manifests, error) {
+	fs := afero.NewMemMapFs()
+	dir, err := untarArchive(fs, content)
+	if err != nil {
+		return nil, err
+	}
+	files, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	var manifests helmchart.Manifests
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		filename := filepath.Join(dir, file.Name())
+		contents, err := afero.ReadFile(fs, filename)
+		if err != nil {
+			return nil, err
+		}
+		// Split file by file, since the order of a map of several files would be random.
+		manifests = append(manifests, installutils.SplitManifests(map[string]string{filename: string(contents)})...)
+	}
+	return manifests, nil
+}
+
+// Sorts manifests by kind in InstallOrder, then by name, like helmchart.RenderManifests does, so that the output does
+// not depend on the order in which the templates were rendered.
+func sortManifestsByKind(manifests helmchart.Manifests) helmchart.Manifests {
+	priority := func(manifest releaseutil.Manifest) int {
+		if priority, ok := installPriorities[manifest.Head.Kind]; ok {
+			return priority
+		}
+		return len(InstallOrder)
+	}
+	sort.SliceStable(manifests, func(i, j int) bool {
+		m1, m2 := manifests[i], manifests[j]
+		if priority1, priority2 := priority(m1), priority(m2); priority1 != priority2 {
+			return priority1 < priority2
+		}
+		if m1.Head.Kind != m2.Head.Kind {
+			return m1.Head.Kind < m2.Head.Kind
+		}
+		return m1.Name < m2.Name
+	})
+	return manifests
+}
+
+func untarArchive(fs afero.Fs, content []byte) (string, error) {
+	tmpDir, err := afero.TempDir(fs, "", "archive-")
+	if err != nil {
+		return "", err
+	}
+	archive := filepath.Join(tmpDir, archiveFilename)
+	if err := afero.WriteFile(fs, archive, content, 0644); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(tmpDir, "content")
+	if err := fs.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if err := tarutils.Untar(dir, archive, fs); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func getSingleDirectory(fs afero.Fs, dir string) (string, error) {
+	files, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return "", err
+	}
+	if len(files) != 1 || !files[0].IsDir() {
+		return "", UnexpectedArchiveLayoutError(len(files))
+	}
+	return filepath.Join(dir, files[0].Name()), nil
+}
+
+func getHelmIgnoreRules(fs afero.Fs, chartDir string) (*helmignore.Rules, error) {
+	rules := helmignore.Empty()
+	content, err := afero.ReadFile(fs, filepath.Join(chartDir, helmignore.HelmIgnore))
+	if err == nil {
+		if rules, err = helmignore.Parse(bytes.NewReader(content)); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse .helmignore")
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "error reading helmignore")
+	}
+	rules.AddDefaults()
+	return rules, nil
+}
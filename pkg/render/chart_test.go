@@ -0,0 +1,110 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+var _ = Describe("chart rendering", func() {
+
+	var c *chart.Chart
+
+	BeforeEach(func() {
+		var err error
+		c, err = render.LoadChartArchive(mustTgz(map[string]string{
+			"app/Chart.yaml": "apiVersion: v1\nname: app\nversion: 0.1.0\nkubeVersion: \">=1.14.0-0\"\n",
+			"app/templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  kubeVersion: {{ .Capabilities.KubeVersion.Version }}
+  minor: "{{ .Capabilities.KubeVersion.Minor }}"
+  monitoring: "{{ .Capabilities.APIVersions.Has "monitoring.coreos.com/v1" }}"
+`,
+		}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("renders for the target kubernetes version and api versions", func() {
+		inputs := render.ValuesInputs{
+			Name:        "app",
+			KubeVersion: "1.17.2",
+			ApiVersions: []string{"monitoring.coreos.com/v1"},
+		}
+		manifests, err := render.RenderChart(context.TODO(), c, "", inputs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests.CombinedString()).To(ContainSubstring("kubeVersion: v1.17.2"))
+		Expect(manifests.CombinedString()).To(ContainSubstring(`minor: "17"`))
+		Expect(manifests.CombinedString()).To(ContainSubstring(`monitoring: "true"`))
+	})
+
+	It("uses helm's defaults when no target is given", func() {
+		manifests, err := render.RenderChart(context.TODO(), c, "", render.ValuesInputs{Name: "app"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests.CombinedString()).To(ContainSubstring(`monitoring: "false"`))
+	})
+
+	It("rejects kubernetes versions the chart is not compatible with", func() {
+		_, err := render.RenderChart(context.TODO(), c, "", render.ValuesInputs{Name: "app", KubeVersion: "v1.13.0"})
+		Expect(err).To(HaveOccurred())
+		_, err = render.RenderChart(context.TODO(), c, "", render.ValuesInputs{Name: "app", KubeVersion: "not-a-version"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("orders the manifests by kind, then by name", func() {
+		templates := map[string]string{"app/Chart.yaml": "apiVersion: v1\nname: app\nversion: 0.1.0\n"}
+		for file, kind := range map[string]string{
+			"deployment.yaml": "Deployment",
+			"service.yaml":    "Service",
+			"b-config.yaml":   "ConfigMap",
+			"a-config.yaml":   "ConfigMap",
+			"sa.yaml":         "ServiceAccount",
+			"widget.yaml":     "Widget",
+			"crd.yaml":        "CustomResourceDefinition",
+		} {
+			templates["app/templates/"+file] = "apiVersion: v1\nkind: " + kind + "\nmetadata:\n  name: " + file + "\n"
+		}
+		c, err := render.LoadChartArchive(mustTgz(templates))
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 10; i++ {
+			manifests, err := render.RenderChart(context.TODO(), c, "", render.ValuesInputs{Name: "app"})
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, manifest := range manifests {
+				names = append(names, manifest.Name)
+			}
+			Expect(names).To(Equal([]string{
+				"app/templates/crd.yaml",
+				"app/templates/sa.yaml",
+				"app/templates/a-config.yaml",
+				"app/templates/b-config.yaml",
+				"app/templates/service.yaml",
+				"app/templates/deployment.yaml",
+				"app/templates/widget.yaml",
+			}))
+		}
+	})
+
+	It("keeps the manifests of plain archives in the order of their files", func() {
+		archive := mustTgz(map[string]string{
+			"1-deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\n",
+			"2-sa.yaml":         "apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: app\n",
+			"3-config.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n",
+		})
+		for i := 0; i < 10; i++ {
+			manifests, err := render.GetManifestsFromArchiveContent(archive)
+			Expect(err).NotTo(HaveOccurred())
+			var kinds []string
+			for _, manifest := range manifests {
+				kinds = append(kinds, manifest.Head.Kind)
+			}
+			Expect(kinds).To(Equal([]string{"Deployment", "ServiceAccount", "ConfigMap"}))
+		}
+	})
+})
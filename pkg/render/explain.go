@@ -0,0 +1,165 @@
+package render
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render/validation"
+)
+
+// The kind of source a value comes from. Sources override each other in the order listed here.
+type ValueSourceType string
+
+const (
+	SpecValuesSource  ValueSourceType = "spec"
+	LayerValuesSource ValueSourceType = "layer"
+	ParamSource       ValueSourceType = "param"
+	UserValuesSource  ValueSourceType = "user"
+)
+
+// A value set by one of the sources coalesced by ComputeValueOverrides.
+type ValueSource struct {
+	Type ValueSourceType `json:"type"`
+	// The layer and option, i.e. "tls/strict", or the param that sets the value. Empty for spec and user values.
+	Name  string      `json:"name,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// The source of the effective value at a leaf path of the values, along with the sources it overrides, highest
+// precedence first.
+type ValueProvenance struct {
+	Path       string        `json:"path"`
+	Source     ValueSource   `json:"source"`
+	Overridden []ValueSource `json:"overridden,omitempty"`
+}
+
+// Explains where each of the values computed for the inputs comes from, by leaf path, in the order of the paths. The
+// inputs are validated, and their templates rendered, like when rendering the spec. The values of secret params are
+// redacted.
+func ExplainValues(ctx context.Context, inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec) ([]ValueProvenance, error) {
+	inputs = withSecretParams(inputs, spec)
+	redactor := newRedactor(inputs)
+	explained, err := explainValues(withRedactedLogger(ctx, redactor), redactor, inputs, spec)
+	if err != nil {
+		return nil, redactor.Error(err)
+	}
+	return explained, nil
+}
+
+func explainValues(ctx context.Context, redactor *redactor, inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec) ([]ValueProvenance, error) {
+	if err := ValidateInputs(inputs, *spec, validation.NoopValidateResources); err != nil {
+		return nil, err
+	}
+	inputs, err := ExecInputValuesTemplates(inputs)
+	if err != nil {
+		return nil, err
+	}
+	redactor.add(inputs)
+	if err := ValidateParamTypes(inputs, spec); err != nil {
+		return nil, err
+	}
+
+	sources, err := getValueSources(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	valuesMap := make(map[string]interface{})
+	for _, source := range sources {
+		// Coalescing modifies the maps, so keep the values of each source intact to look them up afterwards.
+		valuesMap = CoalesceValuesMap(ctx, valuesMap, copyValue(source.values).(map[string]interface{}))
+	}
+
+	secretPaths := make(map[string]bool)
+	for _, name := range inputs.SecretParams {
+		secretPaths[name] = true
+	}
+
+	var explained []ValueProvenance
+	for _, path := range leafPaths(valuesMap, nil) {
+		provenance := ValueProvenance{Path: formatValuePath(path)}
+		found := false
+		for i := len(sources) - 1; i >= 0; i-- {
+			value, ok := lookupValue(sources[i].values, path)
+			if !ok {
+				continue
+			}
+			source := sources[i].source
+			if secretPaths[provenance.Path] || source.Type == ParamSource && secretPaths[source.Name] {
+				source.Value = RedactedValue
+			} else {
+				source.Value = redactor.value(value)
+			}
+			if found {
+				provenance.Overridden = append(provenance.Overridden, source)
+			} else {
+				provenance.Source, found = source, true
+			}
+		}
+		explained = append(explained, provenance)
+	}
+	return explained, nil
+}
+
+// Returns the paths of the values that are not tables, or that are empty tables, ordered by key.
+func leafPaths(values map[string]interface{}, prefix []string) [][]string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var paths [][]string
+	for _, key := range keys {
+		path := append(append([]string{}, prefix...), key)
+		if table, ok := values[key].(map[string]interface{}); ok && len(table) > 0 {
+			paths = append(paths, leafPaths(table, path)...)
+		} else {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func lookupValue(values map[string]interface{}, path []string) (interface{}, bool) {
+	var value interface{} = values
+	for _, key := range path {
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = table[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Joins the keys with dots, escaping the dots in keys like helm's --set does.
+func formatValuePath(path []string) string {
+	escaped := make([]string, len(path))
+	for i, key := range path {
+		escaped[i] = strings.Replace(key, ".", `\.`, -1)
+	}
+	return strings.Join(escaped, ".")
+}
+
+func copyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(t))
+		for key, value := range t {
+			copied[key] = copyValue(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(t))
+		for i, value := range t {
+			copied[i] = copyValue(value)
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,137 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+)
+
+var _ = Describe("explain values", func() {
+
+	var (
+		flavor *v1.Flavor
+		spec   *v1.VersionedApplicationSpec
+		inputs render.ValuesInputs
+	)
+
+	BeforeEach(func() {
+		flavor = &v1.Flavor{
+			Name: "default",
+			CustomizationLayers: []*v1.Layer{{
+				Id: "size",
+				Options: []*v1.LayerOption{{
+					Id:         "small",
+					HelmValues: "replicas: 1\nresources:\n  cpu: 100m\n",
+					Parameters: []*v1.Parameter{{Name: "resources.memory"}},
+				}},
+			}},
+			Parameters: []*v1.Parameter{{Name: "password", Type: v1.ParameterType_SECRET}},
+		}
+		spec = &v1.VersionedApplicationSpec{
+			Parameters: []*v1.Parameter{{Name: "auth.password"}},
+		}
+		inputs = render.ValuesInputs{
+			Name:             "app",
+			InstallNamespace: "install",
+			Flavor:           flavor,
+			Layers:           []render.LayerInput{{LayerId: "size", OptionId: "small"}},
+			Params: map[string]string{
+				"resources.memory": "128Mi",
+				"password":         "hunter2",
+				"auth.password":    "{{ .Params.password }}",
+			},
+			SpecDefinedValues: "replicas: 3\nname: app\nlabels: {}\n",
+			UserDefinedValues: "replicas: 5\n",
+		}
+	})
+
+	provenanceOf := func(explained []render.ValueProvenance, path string) render.ValueProvenance {
+		for _, provenance := range explained {
+			if provenance.Path == path {
+				return provenance
+			}
+		}
+		Fail("no provenance for " + path)
+		return render.ValueProvenance{}
+	}
+
+	It("explains the winning and overridden sources of every leaf path", func() {
+		explained, err := render.ExplainValues(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		var paths []string
+		for _, provenance := range explained {
+			paths = append(paths, provenance.Path)
+		}
+		Expect(paths).To(Equal([]string{
+			"auth.password", "labels", "name", "password", "replicas", "resources.cpu", "resources.memory",
+		}))
+
+		Expect(provenanceOf(explained, "replicas")).To(Equal(render.ValueProvenance{
+			Path:   "replicas",
+			Source: render.ValueSource{Type: render.UserValuesSource, Value: float64(5)},
+			Overridden: []render.ValueSource{
+				{Type: render.LayerValuesSource, Name: "size/small", Value: float64(1)},
+				{Type: render.SpecValuesSource, Value: float64(3)},
+			},
+		}))
+		Expect(provenanceOf(explained, "name").Source).To(Equal(render.ValueSource{Type: render.SpecValuesSource, Value: "app"}))
+		Expect(provenanceOf(explained, "labels").Source).To(Equal(render.ValueSource{
+			Type:  render.SpecValuesSource,
+			Value: map[string]interface{}{},
+		}))
+		Expect(provenanceOf(explained, "resources.cpu").Source).To(Equal(render.ValueSource{
+			Type:  render.LayerValuesSource,
+			Name:  "size/small",
+			Value: "100m",
+		}))
+		Expect(provenanceOf(explained, "resources.memory")).To(Equal(render.ValueProvenance{
+			Path:   "resources.memory",
+			Source: render.ValueSource{Type: render.ParamSource, Name: "resources.memory", Value: "128Mi"},
+		}))
+	})
+
+	It("redacts secret params and the values that reference them", func() {
+		explained, err := render.ExplainValues(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(provenanceOf(explained, "password").Source).To(Equal(render.ValueSource{
+			Type:  render.ParamSource,
+			Name:  "password",
+			Value: render.RedactedValue,
+		}))
+		Expect(provenanceOf(explained, "auth.password").Source).To(Equal(render.ValueSource{
+			Type:  render.ParamSource,
+			Name:  "auth.password",
+			Value: render.RedactedValue,
+		}))
+	})
+
+	It("escapes dots in keys", func() {
+		inputs.UserDefinedValues = "annotations:\n  example.com/owner: team\n"
+		explained, err := render.ExplainValues(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provenanceOf(explained, `annotations.example\.com/owner`).Source).To(Equal(render.ValueSource{
+			Type:  render.UserValuesSource,
+			Value: "team",
+		}))
+	})
+
+	It("returns validation errors", func() {
+		inputs.Layers = nil
+		_, err := render.ExplainValues(context.TODO(), inputs, spec)
+		Expect(err).To(Equal(render.IncorrectNumberOfInputLayersError))
+	})
+
+	It("validates the types of the rendered params", func() {
+		flavor.Parameters = append(flavor.Parameters, &v1.Parameter{Name: "port", Type: v1.ParameterType_INT})
+		inputs.Params["port"] = "{{ .Params.password }}"
+		_, err := render.ExplainValues(context.TODO(), inputs, spec)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("port"))
+		Expect(err.Error()).NotTo(ContainSubstring("hunter2"))
+	})
+})
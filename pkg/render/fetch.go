@@ -0,0 +1,330 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/github"
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/githubutils"
+	"github.com/solo-io/go-utils/tarutils"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/cache"
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+)
+
+// Retrieves the artifacts referenced by installation specs.
+type ArtifactFetcher interface {
+	// Returns the content of the archive found at the given url or local path.
+	FetchArchive(ctx context.Context, uri string) ([]byte, error)
+	// Returns a gzipped tarball of the github repository at the given location's ref.
+	FetchGithubArchive(ctx context.Context, location *hubv1.GithubRepositoryLocation) ([]byte, error)
+	// Returns a gzipped tarball of the content of a directory on the local filesystem.
+	FetchLocalDirectory(ctx context.Context, path string) ([]byte, error)
+	// Returns the chart archive stored as an OCI artifact.
+	FetchOciChart(ctx context.Context, location *hubv1.OciChartLocation) ([]byte, error)
+	// Returns a gzipped tarball of the git repository at the given location's ref.
+	FetchGitArchive(ctx context.Context, location *hubv1.GitRepositoryLocation) ([]byte, error)
+}
+
+// Implemented by fetchers that can make use of the hex sha256 digest of an archive when it is known up front, like the
+// digests of the charts of a helm repository index, e.g. to find the archive in a cache and verify it there.
+type DigestArchiveFetcher interface {
+	FetchArchiveWithDigest(ctx context.Context, uri, digest string) ([]byte, error)
+}
+
+type remoteArtifactFetcher struct{}
+
+// Fetches every artifact from its location.
+func NewRemoteArtifactFetcher() ArtifactFetcher {
+	return &remoteArtifactFetcher{}
+}
+
+func (f *remoteArtifactFetcher) FetchArchive(ctx context.Context, uri string) ([]byte, error) {
+	contextutils.LoggerFrom(ctx).Infow("Downloading archive", zap.String("uri", uri))
+	file, err := tarutils.RetrieveArchive(afero.NewOsFs(), uri)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ioutil.ReadAll(file)
+}
+
+func (f *remoteArtifactFetcher) FetchGithubArchive(ctx context.Context, location *hubv1.GithubRepositoryLocation) ([]byte, error) {
+	contextutils.LoggerFrom(ctx).Infow("Downloading github repository", zap.Any("location", location))
+	buf := new(bytes.Buffer)
+	if err := githubutils.DownloadRepoArchive(ctx, github.NewClient(nil), buf, location.Org, location.Repo, location.Ref); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (f *remoteArtifactFetcher) FetchLocalDirectory(ctx context.Context, path string) ([]byte, error) {
+	contextutils.LoggerFrom(ctx).Infow("Reading local directory", zap.String("path", path))
+	buf := new(bytes.Buffer)
+	if err := tarutils.Tar(filepath.Clean(path), afero.NewOsFs(), buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (f *remoteArtifactFetcher) FetchOciChart(ctx context.Context, location *hubv1.OciChartLocation) ([]byte, error) {
+	return FetchOciChartArchive(ctx, location)
+}
+
+func (f *remoteArtifactFetcher) FetchGitArchive(ctx context.Context, location *hubv1.GitRepositoryLocation) ([]byte, error) {
+	return FetchGitRepositoryArchive(ctx, location)
+}
+
+// Full git commit shas identify immutable repository content.
+var commitShaRegex = regexp.MustCompile("^[0-9a-f]{40}$")
+
+type cachingArtifactFetcher struct {
+	cache   *cache.ArtifactCache
+	fetcher ArtifactFetcher
+}
+
+// Serves artifacts from the cache, falling back to the given fetcher for artifacts that are missing or stale.
+func NewCachingArtifactFetcher(artifactCache *cache.ArtifactCache, fetcher ArtifactFetcher) ArtifactFetcher {
+	return &cachingArtifactFetcher{
+		cache:   artifactCache,
+		fetcher: fetcher,
+	}
+}
+
+func (f *cachingArtifactFetcher) FetchArchive(ctx context.Context, uri string) ([]byte, error) {
+	return f.cache.Get(cache.Key("archive", uri), uri, "", false, func() ([]byte, error) {
+		return f.fetcher.FetchArchive(ctx, uri)
+	})
+}
+
+// The archive is looked up by its digest, and verified against it whether it is read from the cache or fetched.
+func (f *cachingArtifactFetcher) FetchArchiveWithDigest(ctx context.Context, uri, digest string) ([]byte, error) {
+	return f.cache.Get(cache.Key("archive", uri), uri, digest, true, func() ([]byte, error) {
+		return f.fetcher.FetchArchive(ctx, uri)
+	})
+}
+
+func (f *cachingArtifactFetcher) FetchGithubArchive(ctx context.Context, location *hubv1.GithubRepositoryLocation) ([]byte, error) {
+	description := "github.com/" + location.Org + "/" + location.Repo + "@" + location.Ref
+	key := cache.Key("github", location.Org, location.Repo, location.Ref)
+	return f.cache.Get(key, description, "", commitShaRegex.MatchString(location.Ref), func() ([]byte, error) {
+		return f.fetcher.FetchGithubArchive(ctx, location)
+	})
+}
+
+// Local directories are read every time, since their content is expected to change while it is being worked on.
+func (f *cachingArtifactFetcher) FetchLocalDirectory(ctx context.Context, path string) ([]byte, error) {
+	return f.fetcher.FetchLocalDirectory(ctx, path)
+}
+
+// Charts pinned to a digest are pulled blob by blob through the cache, so that the manifest and the chart layer are
+// verified against their digests whenever they are read. Charts referenced by tag are cached like other archives.
+func (f *cachingArtifactFetcher) FetchOciChart(ctx context.Context, location *hubv1.OciChartLocation) ([]byte, error) {
+	ref, err := ParseOciReference(location)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Digest != "" {
+		return fetchOciChartArchive(ctx, ref, &cachingOciStore{ociStore: newOciStore(ref), cache: f.cache})
+	}
+	key := cache.Key("oci", location.GetReference())
+	return f.cache.Get(key, location.GetReference(), "", false, func() ([]byte, error) {
+		return f.fetcher.FetchOciChart(ctx, location)
+	})
+}
+
+// Caches the manifests and blobs of an OCI store by digest. Only used for references pinned to a digest, since
+// manifests referenced by tag cannot be verified.
+type cachingOciStore struct {
+	ociStore
+	cache *cache.ArtifactCache
+}
+
+func (s *cachingOciStore) Manifest(ctx context.Context, ref *OciReference) ([]byte, error) {
+	return s.get(ref, ref.Digest, func() ([]byte, error) {
+		return s.ociStore.Manifest(ctx, ref)
+	})
+}
+
+func (s *cachingOciStore) Blob(ctx context.Context, ref *OciReference, digest string) ([]byte, error) {
+	return s.get(ref, digest, func() ([]byte, error) {
+		return s.ociStore.Blob(ctx, ref, digest)
+	})
+}
+
+func (s *cachingOciStore) get(ref *OciReference, digest string, fetch func() ([]byte, error)) ([]byte, error) {
+	if err := validateOciDigest(digest); err != nil {
+		return nil, err
+	}
+	// The cache identifies content by its hex sha256 digest.
+	hash := strings.TrimPrefix(digest, "sha256:")
+	return s.cache.Get(cache.Key("oci", digest), ref.String()+" "+digest, hash, true, fetch)
+}
+
+func (f *cachingArtifactFetcher) FetchGitArchive(ctx context.Context, location *hubv1.GitRepositoryLocation) ([]byte, error) {
+	description := location.GetUrl() + "@" + location.GetRef()
+	key := cache.Key("git", location.GetUrl(), location.GetRef())
+	return f.cache.Get(key, description, "", commitShaRegex.MatchString(location.GetRef()), func() ([]byte, error) {
+		return f.fetcher.FetchGitArchive(ctx, location)
+	})
+}
+
+// Callbacks for the artifact locations referenced by a spec. Locations are passed by reference so that visitors can
+// rewrite them. Nil callbacks are skipped.
+type ArtifactVisitor struct {
+	Archive        func(location *hubv1.TgzLocation) error
+	Github         func(location *hubv1.GithubRepositoryLocation) error
+	Local          func(location *hubv1.LocalLocation) error
+	HelmRepository func(location *hubv1.HelmRepositoryLocation) error
+	OciChart       func(location *hubv1.OciChartLocation) error
+	Git            func(location *hubv1.GitRepositoryLocation) error
+}
+
+func (v ArtifactVisitor) visitArchive(location *hubv1.TgzLocation) error {
+	if v.Archive == nil {
+		return nil
+	}
+	return v.Archive(location)
+}
+
+func (v ArtifactVisitor) visitGithub(location *hubv1.GithubRepositoryLocation) error {
+	if v.Github == nil {
+		return nil
+	}
+	return v.Github(location)
+}
+
+func (v ArtifactVisitor) visitLocal(location *hubv1.LocalLocation) error {
+	if v.Local == nil {
+		return nil
+	}
+	return v.Local(location)
+}
+
+func (v ArtifactVisitor) visitHelmRepository(location *hubv1.HelmRepositoryLocation) error {
+	if v.HelmRepository == nil {
+		return nil
+	}
+	return v.HelmRepository(location)
+}
+
+func (v ArtifactVisitor) visitOciChart(location *hubv1.OciChartLocation) error {
+	if v.OciChart == nil {
+		return nil
+	}
+	return v.OciChart(location)
+}
+
+func (v ArtifactVisitor) visitGit(location *hubv1.GitRepositoryLocation) error {
+	if v.Git == nil {
+		return nil
+	}
+	return v.Git(location)
+}
+
+// Calls the visitor for every artifact location referenced by the spec, including the kustomize overlays of its flavors.
+func VisitArtifacts(spec *hubv1.VersionedApplicationSpec, visitor ArtifactVisitor) error {
+	var err error
+	switch installationSpec := spec.GetInstallationSpec().(type) {
+	case *hubv1.VersionedApplicationSpec_GithubChart:
+		err = visitor.visitGithub(installationSpec.GithubChart)
+	case *hubv1.VersionedApplicationSpec_HelmArchive:
+		err = visitor.visitArchive(installationSpec.HelmArchive)
+	case *hubv1.VersionedApplicationSpec_ManifestsArchive:
+		err = visitor.visitArchive(installationSpec.ManifestsArchive)
+	case *hubv1.VersionedApplicationSpec_InstallationSteps:
+		for _, step := range installationSpec.InstallationSteps.GetSteps() {
+			if err = visitStepArtifacts(step, visitor); err != nil {
+				break
+			}
+		}
+	case *hubv1.VersionedApplicationSpec_LocalChart:
+		err = visitor.visitLocal(installationSpec.LocalChart)
+	case *hubv1.VersionedApplicationSpec_LocalManifests:
+		err = visitor.visitLocal(installationSpec.LocalManifests)
+	case *hubv1.VersionedApplicationSpec_HelmRepository:
+		err = visitor.visitHelmRepository(installationSpec.HelmRepository)
+	case *hubv1.VersionedApplicationSpec_OciChart:
+		err = visitor.visitOciChart(installationSpec.OciChart)
+	case *hubv1.VersionedApplicationSpec_GitChart:
+		err = visitor.visitGit(installationSpec.GitChart)
+	case *hubv1.VersionedApplicationSpec_InlineManifests:
+		// Inline manifests do not reference any artifact.
+	default:
+		err = MissingInstallSpecError
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, flavor := range spec.GetFlavors() {
+		for _, layer := range flavor.GetCustomizationLayers() {
+			for _, option := range layer.GetOptions() {
+				if github := option.GetKustomize().GetGithub(); github != nil {
+					if err := visitor.visitGithub(github); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func visitStepArtifacts(step *hubv1.InstallationSteps_Step, visitor ArtifactVisitor) error {
+	switch installationSpec := step.GetStep().(type) {
+	case *hubv1.InstallationSteps_Step_GithubChart:
+		return visitor.visitGithub(installationSpec.GithubChart)
+	case *hubv1.InstallationSteps_Step_HelmArchive:
+		return visitor.visitArchive(installationSpec.HelmArchive)
+	case *hubv1.InstallationSteps_Step_ManifestsArchive:
+		return visitor.visitArchive(installationSpec.ManifestsArchive)
+	case *hubv1.InstallationSteps_Step_LocalChart:
+		return visitor.visitLocal(installationSpec.LocalChart)
+	case *hubv1.InstallationSteps_Step_LocalManifests:
+		return visitor.visitLocal(installationSpec.LocalManifests)
+	case *hubv1.InstallationSteps_Step_HelmRepository:
+		return visitor.visitHelmRepository(installationSpec.HelmRepository)
+	case *hubv1.InstallationSteps_Step_OciChart:
+		return visitor.visitOciChart(installationSpec.OciChart)
+	case *hubv1.InstallationSteps_Step_GitChart:
+		return visitor.visitGit(installationSpec.GitChart)
+	case *hubv1.InstallationSteps_Step_InlineManifests:
+		return nil
+	default:
+		return MissingInstallSpecError
+	}
+}
+
+// Fetches every artifact referenced by the spec. This can be used to warm a cache before rendering.
+// Local directories are skipped, since they are always read from disk.
+func FetchArtifacts(ctx context.Context, fetcher ArtifactFetcher, spec *hubv1.VersionedApplicationSpec) error {
+	return VisitArtifacts(spec, ArtifactVisitor{
+		Archive: func(location *hubv1.TgzLocation) error {
+			_, err := fetcher.FetchArchive(ctx, location.GetUri())
+			return err
+		},
+		Github: func(location *hubv1.GithubRepositoryLocation) error {
+			_, err := fetcher.FetchGithubArchive(ctx, location)
+			return err
+		},
+		HelmRepository: func(location *hubv1.HelmRepositoryLocation) error {
+			_, err := FetchHelmRepositoryChart(ctx, fetcher, location)
+			return err
+		},
+		OciChart: func(location *hubv1.OciChartLocation) error {
+			_, err := fetcher.FetchOciChart(ctx, location)
+			return err
+		},
+		Git: func(location *hubv1.GitRepositoryLocation) error {
+			_, err := fetcher.FetchGitArchive(ctx, location)
+			return err
+		},
+	})
+}
@@ -3,6 +3,7 @@ package render
 import (
 	errors "github.com/rotisserie/eris"
 	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render/util"
 )
 
 var (
@@ -83,3 +84,48 @@ func GetLayerOptionFromFlavor(layerId, optionId string, flavor *v1.Flavor) (*v1.
 	}
 	return option, nil
 }
+
+// Returns every combination of options of the flavor's layers. Optional layers are also left out of combinations.
+func GetLayerCombinations(flavor *v1.Flavor) [][]LayerInput {
+	combinations := [][]LayerInput{{}}
+	for _, layer := range flavor.GetCustomizationLayers() {
+		var next [][]LayerInput
+		for _, combination := range combinations {
+			if layer.Optional {
+				next = append(next, combination)
+			}
+			for _, option := range layer.GetOptions() {
+				inputs := append(append([]LayerInput{}, combination...), LayerInput{LayerId: layer.Id, OptionId: option.Id})
+				next = append(next, inputs)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// Returns the default values of the parameters of the version, the flavor and the selected layer options. The defaults
+// of SECRET params are resolved with the resolver.
+func GetDefaultParams(version *v1.VersionedApplicationSpec, flavor *v1.Flavor, layers []LayerInput, secrets util.SecretResolver) (map[string]string, error) {
+	params := append(append([]*v1.Parameter{}, version.GetParameters()...), flavor.GetParameters()...)
+	for _, layer := range layers {
+		option, err := GetLayerOptionFromFlavor(layer.LayerId, layer.OptionId, flavor)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, option.GetParameters()...)
+	}
+
+	values := make(map[string]string, len(params))
+	for _, param := range params {
+		if param.GetDefault() == nil {
+			continue
+		}
+		value, err := util.ParamValueToString(param.GetDefault(), secrets.ResolveSecret)
+		if err != nil {
+			return nil, err
+		}
+		values[param.GetName()] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,154 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+)
+
+var (
+	FailedToRunGitError = func(err error, args []string, output string) error {
+		return errors.Wrapf(err, "git %v failed: %v", strings.Join(args, " "), strings.TrimSpace(output))
+	}
+
+	InvalidGitUrlError = func(url string) error {
+		return errors.Errorf("invalid git url %q: must not start with -", url)
+	}
+
+	InvalidGitRefError = func(ref string) error {
+		return errors.Errorf("invalid git ref %q: must not start with -", ref)
+	}
+
+	UnsupportedGitVersionError = func(version string) error {
+		return errors.Errorf("git %v or later is required to fetch git repositories, found %q", minGitVersion, version)
+	}
+)
+
+// The first version of git that supports --end-of-options, which keeps refs from being mistaken for options.
+const (
+	minGitMajor   = 2
+	minGitMinor   = 24
+	minGitVersion = "2.24"
+)
+
+var (
+	gitVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)`)
+	commitIdPattern   = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+)
+
+// Returns a gzipped tarball of the git repository at the location's ref. Like github archives, the content of the
+// repository is nested in a single top-level directory.
+// Only the commit at the ref is fetched, with the git binary, so any url and credential helper that git supports can be
+// used.
+func FetchGitRepositoryArchive(ctx context.Context, location *hubv1.GitRepositoryLocation) ([]byte, error) {
+	contextutils.LoggerFrom(ctx).Infow("Fetching git repository",
+		zap.String("url", location.GetUrl()),
+		zap.String("ref", location.GetRef()))
+	// Urls and refs are passed to git as arguments, so they must not be mistaken for options, e.g. --upload-pack.
+	if strings.HasPrefix(location.GetUrl(), "-") {
+		return nil, InvalidGitUrlError(location.GetUrl())
+	}
+	if strings.HasPrefix(location.GetRef(), "-") {
+		return nil, InvalidGitRefError(location.GetRef())
+	}
+	if err := checkGitVersion(ctx); err != nil {
+		return nil, err
+	}
+	tmpDir, err := ioutil.TempDir("", "git-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if _, err := runGit(ctx, "", "init", "--bare", "--quiet", tmpDir); err != nil {
+		return nil, err
+	}
+	if _, err := runGit(ctx, tmpDir, "config", "remote.origin.url", location.GetUrl()); err != nil {
+		return nil, err
+	}
+	ref := location.GetRef()
+	if ref == "" {
+		ref = "HEAD"
+	}
+	fetched := "FETCH_HEAD"
+	if _, err := runGit(ctx, tmpDir, "fetch", "--depth=1", "--quiet", "--end-of-options", "origin", ref); err != nil {
+		if !commitIdPattern.MatchString(ref) {
+			return nil, err
+		}
+		// Some servers only serve the commits that branches and tags point to, fetch their history without the file
+		// contents instead, which are then fetched for the commit only when it is archived.
+		contextutils.LoggerFrom(ctx).Debugw("Unable to fetch the commit alone, fetching the history of the repository",
+			zap.Error(err))
+		if _, err := runGit(ctx, tmpDir, "fetch", "--filter=blob:none", "--quiet", "--end-of-options", "origin",
+			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"); err != nil {
+			return nil, err
+		}
+		fetched = ref
+	}
+	// Resolve the ref first, so that only a commit id is passed to archive.
+	commit, err := runGit(ctx, tmpDir, "rev-parse", "--verify", "--quiet", "--end-of-options", fetched+"^{commit}")
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSuffix(path.Base(strings.TrimSuffix(location.GetUrl(), "/")), ".git") + "/"
+	return runGit(ctx, tmpDir, "archive", "--format=tar.gz", "--prefix="+prefix, strings.TrimSpace(string(commit)))
+}
+
+// Fails with a clear error rather than with git's usage when the installed git does not support the options used.
+func checkGitVersion(ctx context.Context) error {
+	output, err := runGit(ctx, "", "version")
+	if err != nil {
+		return err
+	}
+	version := strings.TrimSpace(string(output))
+	if !IsSupportedGitVersion(version) {
+		return UnsupportedGitVersionError(version)
+	}
+	return nil
+}
+
+// Returns whether the output of git version, e.g. "git version 2.24.3 (Apple Git-128)", is at least minGitVersion.
+func IsSupportedGitVersion(version string) bool {
+	match := gitVersionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return false
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	return major > minGitMajor || major == minGitMajor && minor >= minGitMinor
+}
+
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Fail rather than hang when credentials are missing.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		return nil, FailedToRunGitError(err, args, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// Extracts a gzipped tarball of a repository, as returned by github or FetchGitRepositoryArchive, into fs.
+// Returns the directory holding the content of the repository.
+func UntarRepositoryArchive(fs afero.Fs, content []byte) (string, error) {
+	dir, err := untarArchive(fs, content)
+	if err != nil {
+		return "", err
+	}
+	return getSingleDirectory(fs, dir)
+}
@@ -0,0 +1,122 @@
+package render_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+)
+
+var _ = Describe("git installation source", func() {
+
+	var (
+		dir      string
+		bareRepo string
+		commit   string
+	)
+
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "git-source-")
+		Expect(err).NotTo(HaveOccurred())
+		workDir := filepath.Join(dir, "work")
+		Expect(os.MkdirAll(filepath.Join(workDir, "install", "chart", "templates"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(workDir, "install", "chart", "Chart.yaml"),
+			[]byte("apiVersion: v1\nname: app\nversion: 0.1.0\n"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(workDir, "install", "chart", "templates", "configmap.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+`), 0644)).To(Succeed())
+
+		git(workDir, "init", "--quiet")
+		git(workDir, "add", ".")
+		git(workDir, "commit", "--quiet", "-m", "add chart")
+		git(workDir, "tag", "v0.1.0")
+		commit = git(workDir, "rev-parse", "HEAD")
+		bareRepo = filepath.Join(dir, "charts.git")
+		git(dir, "clone", "--bare", "--quiet", workDir, bareRepo)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	inputs := render.ValuesInputs{
+		Name:             "app",
+		InstallNamespace: "install",
+		Flavor:           &v1.Flavor{},
+	}
+
+	for _, ref := range []string{"v0.1.0", "commit"} {
+		ref := ref
+		It("renders a chart from a local bare repository at "+ref, func() {
+			if ref == "commit" {
+				ref = commit
+			}
+			spec := &v1.VersionedApplicationSpec{
+				InstallationSpec: &v1.VersionedApplicationSpec_GitChart{
+					GitChart: &v1.GitRepositoryLocation{Url: bareRepo, Ref: ref, Directory: "install/chart"},
+				},
+			}
+			manifests, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifests.CombinedString()).To(ContainSubstring("name: app-config"))
+		})
+	}
+
+	It("fails for unknown refs", func() {
+		_, err := render.FetchGitRepositoryArchive(context.TODO(), &v1.GitRepositoryLocation{Url: bareRepo, Ref: "missing"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects urls that would be passed to git as options", func() {
+		marker := filepath.Join(dir, "marker")
+		_, err := render.FetchGitRepositoryArchive(context.TODO(), &v1.GitRepositoryLocation{
+			Url: "--upload-pack=touch " + marker,
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid git url"))
+		Expect(marker).NotTo(BeAnExistingFile())
+	})
+
+	It("rejects refs that would be passed to git as options", func() {
+		output := filepath.Join(dir, "output.tar.gz")
+		_, err := render.FetchGitRepositoryArchive(context.TODO(), &v1.GitRepositoryLocation{
+			Url: bareRepo,
+			Ref: "--output=" + output,
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid git ref"))
+		Expect(output).NotTo(BeAnExistingFile())
+	})
+
+	It("requires a git version that supports --end-of-options", func() {
+		for version, supported := range map[string]bool{
+			"git version 2.39.5":                   true,
+			"git version 2.24.3 (Apple Git-128)":   true,
+			"git version 3.0.0":                    true,
+			"git version 2.30.0.windows.1":         true,
+			"git version 2.23.0":                   false,
+			"git version 1.8.3.1":                  false,
+			"git: 'version' is not a git command.": false,
+		} {
+			Expect(render.IsSupportedGitVersion(version)).To(Equal(supported), version)
+		}
+	})
+})
@@ -0,0 +1,147 @@
+package render
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+)
+
+const helmRepositoryIndexFile = "index.yaml"
+
+var (
+	FailedToResolveHelmChartError = func(err error, location *hubv1.HelmRepositoryLocation) error {
+		return errors.Wrapf(err, "unable to resolve chart %v version %q in helm repository %v",
+			location.GetChart(), location.GetVersion(), location.GetRepositoryUrl())
+	}
+
+	ChartDigestMismatchError = func(chartUrl, expected, actual string) error {
+		return errors.Errorf("digest of chart %v does not match the repository index: expected %v, found %v",
+			chartUrl, expected, actual)
+	}
+)
+
+// Returns the url of the index of a helm repository.
+func HelmRepositoryIndexUrl(repositoryUrl string) string {
+	return strings.TrimSuffix(repositoryUrl, "/") + "/" + helmRepositoryIndexFile
+}
+
+// Resolves the chart version from the repository's index, then downloads the chart archive and verifies it against
+// the digest in the index. The index and the archive are both retrieved with the fetcher.
+func FetchHelmRepositoryChart(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.HelmRepositoryLocation) ([]byte, error) {
+	indexUrl := HelmRepositoryIndexUrl(location.GetRepositoryUrl())
+	indexContent, err := fetcher.FetchArchive(ctx, indexUrl)
+	if err != nil {
+		return nil, FailedToResolveHelmChartError(err, location)
+	}
+	chartVersion, err := ResolveHelmChartVersion(indexContent, location)
+	if err != nil {
+		return nil, FailedToResolveHelmChartError(err, location)
+	}
+	chartUrl, err := resolveChartUrl(indexUrl, chartVersion.URLs[0])
+	if err != nil {
+		return nil, FailedToResolveHelmChartError(err, location)
+	}
+
+	contextutils.LoggerFrom(ctx).Infow("Resolved chart from helm repository",
+		zap.String("chart", location.GetChart()),
+		zap.String("version", chartVersion.Version),
+		zap.String("url", chartUrl))
+	var content []byte
+	if digestFetcher, ok := fetcher.(DigestArchiveFetcher); ok && chartVersion.Digest != "" {
+		content, err = digestFetcher.FetchArchiveWithDigest(ctx, chartUrl, chartVersion.Digest)
+	} else {
+		content, err = fetcher.FetchArchive(ctx, chartUrl)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if chartVersion.Digest != "" {
+		sum := sha256.Sum256(content)
+		if actual := hex.EncodeToString(sum[:]); actual != chartVersion.Digest {
+			return nil, ChartDigestMismatchError(chartUrl, chartVersion.Digest, actual)
+		}
+	}
+	return content, nil
+}
+
+// The subset of a helm repository index needed to resolve charts.
+type HelmRepositoryIndex struct {
+	Entries map[string][]*HelmChartVersion `json:"entries"`
+}
+
+type HelmChartVersion struct {
+	Version string   `json:"version"`
+	URLs    []string `json:"urls"`
+	Digest  string   `json:"digest"`
+}
+
+// Finds the newest version of the chart in the index that satisfies the location's version. Exact matches win over
+// constraints, like they do in helm.
+func ResolveHelmChartVersion(indexContent []byte, location *hubv1.HelmRepositoryLocation) (*HelmChartVersion, error) {
+	var index HelmRepositoryIndex
+	if err := yaml.Unmarshal(indexContent, &index); err != nil {
+		return nil, err
+	}
+	chartVersions, ok := index.Entries[location.GetChart()]
+	if !ok || len(chartVersions) == 0 {
+		return nil, errors.Errorf("chart %v not found in repository index", location.GetChart())
+	}
+
+	version := location.GetVersion()
+	if version == "" {
+		version = "*"
+	}
+	for _, chartVersion := range chartVersions {
+		if chartVersion.Version == version {
+			return validChartVersion(location, chartVersion)
+		}
+	}
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return nil, err
+	}
+	var newest *HelmChartVersion
+	var newestVersion *semver.Version
+	for _, chartVersion := range chartVersions {
+		v, err := semver.NewVersion(chartVersion.Version)
+		if err != nil || !constraint.Check(v) {
+			continue
+		}
+		if newestVersion == nil || v.GreaterThan(newestVersion) {
+			newest, newestVersion = chartVersion, v
+		}
+	}
+	if newest == nil {
+		return nil, errors.Errorf("no version of chart %v satisfies %v", location.GetChart(), version)
+	}
+	return validChartVersion(location, newest)
+}
+
+func validChartVersion(location *hubv1.HelmRepositoryLocation, chartVersion *HelmChartVersion) (*HelmChartVersion, error) {
+	if len(chartVersion.URLs) == 0 {
+		return nil, errors.Errorf("no url for version %v of chart %v", chartVersion.Version, location.GetChart())
+	}
+	return chartVersion, nil
+}
+
+// Chart urls in an index may be relative to the index.
+func resolveChartUrl(indexUrl, chartUrl string) (string, error) {
+	base, err := url.Parse(indexUrl)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(chartUrl)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
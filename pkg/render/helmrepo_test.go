@@ -0,0 +1,145 @@
+package render_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/cache"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+)
+
+func mustTgz(files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	Expect(gzw.Close()).To(Succeed())
+	return buf.Bytes()
+}
+
+var _ = Describe("helm repository installation source", func() {
+
+	var (
+		server     *httptest.Server
+		chart      []byte
+		digest     string
+		chartPulls int
+	)
+
+	BeforeEach(func() {
+		chart = mustTgz(map[string]string{
+			"app/Chart.yaml": "apiVersion: v1\nname: app\nversion: 1.1.0\n",
+			"app/templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+`,
+		})
+		sum := sha256.Sum256(chart)
+		digest = hex.EncodeToString(sum[:])
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/charts/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `apiVersion: v1
+entries:
+  app:
+  - version: 1.0.0
+    urls: [app-1.0.0.tgz]
+  - version: 1.1.0
+    urls: [app-1.1.0.tgz]
+    digest: %v
+  - version: 2.0.0-beta.1
+    urls: [app-2.0.0-beta.1.tgz]
+`, digest)
+		})
+		chartPulls = 0
+		mux.HandleFunc("/charts/app-1.1.0.tgz", func(w http.ResponseWriter, r *http.Request) {
+			chartPulls++
+			w.Write(chart)
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	inputs := render.ValuesInputs{
+		Name:             "app",
+		InstallNamespace: "install",
+		Flavor:           &v1.Flavor{},
+	}
+
+	spec := func(version string) *v1.VersionedApplicationSpec {
+		return &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_HelmRepository{
+				HelmRepository: &v1.HelmRepositoryLocation{
+					RepositoryUrl: server.URL + "/charts/",
+					Chart:         "app",
+					Version:       version,
+				},
+			},
+		}
+	}
+
+	It("renders the newest stable chart version satisfying the constraint", func() {
+		manifests, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec("~1"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests.CombinedString()).To(ContainSubstring("name: app-config"))
+
+		manifests, err = render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec(""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests.CombinedString()).To(ContainSubstring("name: app-config"))
+	})
+
+	It("rejects charts that do not match the digest in the index", func() {
+		chart = mustTgz(map[string]string{"app/Chart.yaml": "apiVersion: v1\nname: app\nversion: 1.1.0\n"})
+		_, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec("1.1.0"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match the repository index"))
+	})
+
+	It("fails when no version satisfies the constraint", func() {
+		_, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec(">=3"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("serves cached charts only while they match the digest in the index", func() {
+		dir, err := ioutil.TempDir("", "helm-cache-")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		fetcher := render.NewCachingArtifactFetcher(cache.NewArtifactCache(dir), render.NewRemoteArtifactFetcher())
+		location := spec("1.1.0").GetHelmRepository()
+
+		for i := 0; i < 2; i++ {
+			content, err := render.FetchHelmRepositoryChart(context.TODO(), fetcher, location)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(chart))
+		}
+		Expect(chartPulls).To(Equal(1))
+
+		Expect(ioutil.WriteFile(filepath.Join(dir, "blobs", digest), []byte("tampered"), 0644)).To(Succeed())
+		content, err := render.FetchHelmRepositoryChart(context.TODO(), fetcher, location)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal(chart))
+		Expect(chartPulls).To(Equal(2))
+	})
+})
@@ -0,0 +1,326 @@
+package render
+
+import (
+	"context"
+	"strings"
+
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	DefaultImageRegistry = "docker.io"
+	defaultImageTag      = "latest"
+)
+
+var (
+	InvalidImageReferenceError = func(image string) error {
+		return errors.Errorf("invalid image reference %q", image)
+	}
+
+	FailedToRewriteImagesError = func(err error, resource *unstructured.Unstructured) error {
+		return errors.Wrapf(err, "failed to rewrite images of %v %v", resource.GetKind(), resource.GetName())
+	}
+)
+
+// Where the container images of a kind of resource are found.
+type imageLocations struct {
+	// Paths to pod specs, or other objects holding lists of containers
+	podSpecs [][]string
+	// Paths to fields holding a single image
+	images [][]string
+}
+
+var workloadImageLocations = map[string]imageLocations{
+	"Pod":                   {podSpecs: [][]string{{"spec"}}},
+	"Deployment":            {podSpecs: [][]string{{"spec", "template", "spec"}}},
+	"StatefulSet":           {podSpecs: [][]string{{"spec", "template", "spec"}}},
+	"DaemonSet":             {podSpecs: [][]string{{"spec", "template", "spec"}}},
+	"ReplicaSet":            {podSpecs: [][]string{{"spec", "template", "spec"}}},
+	"ReplicationController": {podSpecs: [][]string{{"spec", "template", "spec"}}},
+	"Job":                   {podSpecs: [][]string{{"spec", "template", "spec"}}},
+	"CronJob":               {podSpecs: [][]string{{"spec", "jobTemplate", "spec", "template", "spec"}}},
+}
+
+// Custom resources known to run containers, keyed by api group and kind.
+var customResourceImageLocations = map[string]imageLocations{
+	"monitoring.coreos.com/Prometheus":   {podSpecs: [][]string{{"spec"}}, images: [][]string{{"spec", "image"}}},
+	"monitoring.coreos.com/Alertmanager": {podSpecs: [][]string{{"spec"}}, images: [][]string{{"spec", "image"}}},
+	"monitoring.coreos.com/ThanosRuler":  {podSpecs: [][]string{{"spec"}}, images: [][]string{{"spec", "image"}}},
+	"serving.knative.dev/Service":        {podSpecs: [][]string{{"spec", "template", "spec"}}},
+	"serving.knative.dev/Configuration":  {podSpecs: [][]string{{"spec", "template", "spec"}}},
+	"argoproj.io/Rollout":                {podSpecs: [][]string{{"spec", "template", "spec"}}},
+}
+
+var containerFields = []string{"initContainers", "containers", "ephemeralContainers"}
+
+// Called with the name of the container, or the path of the field, holding each image. Returns the image to replace
+// it with.
+type imageVisitor func(container, image string) (string, error)
+
+func visitImages(resource *unstructured.Unstructured, visit imageVisitor) error {
+	locations, ok := customResourceImageLocations[resource.GroupVersionKind().Group+"/"+resource.GetKind()]
+	if !ok {
+		locations = workloadImageLocations[resource.GetKind()]
+	}
+	for _, podSpecPath := range locations.podSpecs {
+		for _, field := range containerFields {
+			path := append(append([]string{}, podSpecPath...), field)
+			containers, found, err := unstructured.NestedSlice(resource.Object, path...)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				image, ok := container["image"].(string)
+				if !ok || image == "" {
+					continue
+				}
+				name, _ := container["name"].(string)
+				if container["image"], err = visit(name, image); err != nil {
+					return err
+				}
+			}
+			if err := unstructured.SetNestedSlice(resource.Object, containers, path...); err != nil {
+				return err
+			}
+		}
+	}
+	for _, path := range locations.images {
+		image, found, err := unstructured.NestedString(resource.Object, path...)
+		if err != nil {
+			return err
+		}
+		if !found || image == "" {
+			continue
+		}
+		if image, err = visit(strings.Join(path, "."), image); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(resource.Object, image, path...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// An image referenced by a rendered resource.
+type ContainerImage struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Name of the container, or path of the field holding the image
+	Container string
+	Image     string
+}
+
+// Returns every image referenced by the containers of workloads, including init containers, and of known custom
+// resources.
+func ExtractImages(resources kuberesource.UnstructuredResources) ([]ContainerImage, error) {
+	var images []ContainerImage
+	for _, resource := range resources {
+		err := visitImages(resource, func(container, image string) (string, error) {
+			images = append(images, ContainerImage{
+				Kind:      resource.GetKind(),
+				Namespace: resource.GetNamespace(),
+				Name:      resource.GetName(),
+				Container: container,
+				Image:     image,
+			})
+			return image, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return images, nil
+}
+
+// A parsed container image reference, normalized like docker does: images without a registry are on docker hub, and
+// official images live under library/.
+type ImageReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+func ParseImageReference(image string) (*ImageReference, error) {
+	ref := &ImageReference{}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+	}
+	// A colon after the last slash separates the tag, anything before it may be a registry port.
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+	if name == "" || strings.ContainsAny(name, " \t") || (ref.Digest == "" && strings.Contains(image, "@")) {
+		return nil, InvalidImageReferenceError(image)
+	}
+
+	ref.Registry, ref.Repository = splitRegistry(name)
+	if ref.Registry == "" {
+		ref.Registry = DefaultImageRegistry
+	}
+	if ref.Registry == DefaultImageRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultImageTag
+	}
+	return ref, nil
+}
+
+// Splits the registry, if any, from the repository path of an image name without a tag or digest, as written.
+func splitRegistry(name string) (string, string) {
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0], parts[1]
+	}
+	return "", name
+}
+
+// Splits an image as written into its name and the suffix holding its tag and digest, e.g. ":1.0@sha256:...".
+func splitImageVersion(image string) (string, string) {
+	i := strings.Index(image, "@")
+	if i < 0 {
+		i = len(image)
+	}
+	if j := strings.LastIndex(image[:i], ":"); j > strings.LastIndex(image[:i], "/") {
+		i = j
+	}
+	return image[:i], image[i:]
+}
+
+// Returns the registry and repository of the image, without its tag or digest.
+func (r *ImageReference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+func (r *ImageReference) String() string {
+	s := r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// Records an image reference of a container that was rewritten.
+type ImageRewrite struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Container string
+	From      string
+	To        string
+}
+
+// Rewrites the images of the containers of every workload and known custom resource, e.g. to pull them from a private mirror.
+type ImageRewriter struct {
+	// Images, or image names without a tag or digest, mapped to their replacement. Mapping a name keeps the tag and
+	// digest of the original image, unless the replacement has its own. Mappings take precedence over the registry.
+	// Images are matched in their normalized form, e.g. nginx matches docker.io/library/nginx:latest.
+	Mappings map[string]string
+	// When set, the registry of every image that is not mapped is replaced with it, keeping the repository path, tag
+	// and digest as written, e.g. nginx is pulled from <registry>/nginx.
+	Registry string
+	// Called for every rewritten image, in addition to logging it.
+	OnRewrite func(rewrite ImageRewrite)
+}
+
+func (r *ImageRewriter) Transform(ctx context.Context, _ ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	mappings := make(map[string]string, len(r.Mappings))
+	for from, to := range r.Mappings {
+		fromRef, err := ParseImageReference(from)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := ParseImageReference(to); err != nil {
+			return nil, err
+		}
+		key := fromRef.Name()
+		if hasExplicitVersion(from) {
+			key = fromRef.String()
+		}
+		mappings[key] = to
+	}
+
+	for _, resource := range resources {
+		if err := r.rewriteResource(ctx, resource, mappings); err != nil {
+			return nil, FailedToRewriteImagesError(err, resource)
+		}
+	}
+	return resources, nil
+}
+
+func (r *ImageRewriter) rewriteResource(ctx context.Context, resource *unstructured.Unstructured, mappings map[string]string) error {
+	return visitImages(resource, func(container, image string) (string, error) {
+		rewritten, err := r.rewriteImage(image, mappings)
+		if err != nil || rewritten == image {
+			return image, err
+		}
+		rewrite := ImageRewrite{
+			Kind:      resource.GetKind(),
+			Namespace: resource.GetNamespace(),
+			Name:      resource.GetName(),
+			Container: container,
+			From:      image,
+			To:        rewritten,
+		}
+		contextutils.LoggerFrom(ctx).Infow("Rewrote container image",
+			zap.String("kind", rewrite.Kind),
+			zap.String("namespace", rewrite.Namespace),
+			zap.String("name", rewrite.Name),
+			zap.String("container", rewrite.Container),
+			zap.String("from", rewrite.From),
+			zap.String("to", rewrite.To))
+		if r.OnRewrite != nil {
+			r.OnRewrite(rewrite)
+		}
+		return rewritten, nil
+	})
+}
+
+// Images are only normalized to be matched, the images they are rewritten to keep the repository path, tag and digest
+// as written.
+func (r *ImageRewriter) rewriteImage(image string, mappings map[string]string) (string, error) {
+	ref, err := ParseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+	if to, ok := mappings[ref.String()]; ok {
+		return to, nil
+	}
+	name, version := splitImageVersion(image)
+	if to, ok := mappings[ref.Name()]; ok {
+		if hasExplicitVersion(to) {
+			return to, nil
+		}
+		return to + version, nil
+	}
+	if r.Registry != "" && ref.Registry != r.Registry {
+		_, repository := splitRegistry(name)
+		return r.Registry + "/" + repository + version, nil
+	}
+	return image, nil
+}
+
+func hasExplicitVersion(image string) bool {
+	_, version := splitImageVersion(image)
+	return version != ""
+}
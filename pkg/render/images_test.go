@@ -0,0 +1,214 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("container images", func() {
+
+	Context("parsing image references", func() {
+		It("normalizes docker hub images", func() {
+			ref, err := render.ParseImageReference("nginx")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*ref).To(Equal(render.ImageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}))
+		})
+
+		It("parses registries with ports, tags and digests", func() {
+			ref, err := render.ParseImageReference("localhost:5000/istio/proxyv2:1.4.3@sha256:abc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*ref).To(Equal(render.ImageReference{
+				Registry:   "localhost:5000",
+				Repository: "istio/proxyv2",
+				Tag:        "1.4.3",
+				Digest:     "sha256:abc",
+			}))
+			Expect(ref.String()).To(Equal("localhost:5000/istio/proxyv2:1.4.3@sha256:abc"))
+		})
+
+		It("rejects invalid references", func() {
+			_, err := render.ParseImageReference("nginx@")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	const manifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: app
+        image: quay.io/app/server:1.0
+      - name: proxy
+        image: istio/proxyv2@sha256:abc
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: cleanup
+            image: gcr.io/project/cleanup:2.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  image: nginx
+`
+
+	var resources kuberesource.UnstructuredResources
+
+	BeforeEach(func() {
+		var err error
+		resources, err = render.YamlToResources([]byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	images := func(resource *unstructured.Unstructured, path ...string) []string {
+		containers, _, err := unstructured.NestedSlice(resource.Object, path...)
+		Expect(err).NotTo(HaveOccurred())
+		var images []string
+		for _, c := range containers {
+			images = append(images, c.(map[string]interface{})["image"].(string))
+		}
+		return images
+	}
+
+	It("moves every image to the registry and reports the rewrites", func() {
+		var rewrites []render.ImageRewrite
+		rewriter := &render.ImageRewriter{
+			Registry:  "mirror.example.com",
+			OnRewrite: func(rewrite render.ImageRewrite) { rewrites = append(rewrites, rewrite) },
+		}
+		transformed, err := rewriter.Transform(context.TODO(), render.ValuesInputs{}, resources)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(images(transformed[0], "spec", "template", "spec", "initContainers")).To(Equal([]string{
+			"mirror.example.com/busybox",
+		}))
+		Expect(images(transformed[0], "spec", "template", "spec", "containers")).To(Equal([]string{
+			"mirror.example.com/app/server:1.0",
+			"mirror.example.com/istio/proxyv2@sha256:abc",
+		}))
+		Expect(images(transformed[1], "spec", "jobTemplate", "spec", "template", "spec", "containers")).To(Equal([]string{
+			"mirror.example.com/project/cleanup:2.0",
+		}))
+		Expect(transformed[2].Object["data"]).To(HaveKeyWithValue("image", "nginx"))
+
+		Expect(rewrites).To(HaveLen(4))
+		Expect(rewrites[0]).To(Equal(render.ImageRewrite{
+			Kind:      "Deployment",
+			Name:      "app",
+			Container: "init",
+			From:      "busybox",
+			To:        "mirror.example.com/busybox",
+		}))
+	})
+
+	It("logs the rewrites to the logger of the context", func() {
+		core, logs := observer.New(zapcore.InfoLevel)
+		ctx := contextutils.WithExistingLogger(context.TODO(), zap.New(core).Sugar())
+		rewriter := &render.ImageRewriter{Registry: "mirror.example.com"}
+		_, err := rewriter.Transform(ctx, render.ValuesInputs{}, resources)
+		Expect(err).NotTo(HaveOccurred())
+
+		rewrites := logs.FilterMessage("Rewrote container image").All()
+		Expect(rewrites).To(HaveLen(4))
+		Expect(rewrites[0].ContextMap()).To(Equal(map[string]interface{}{
+			"kind":      "Deployment",
+			"namespace": "",
+			"name":      "app",
+			"container": "init",
+			"from":      "busybox",
+			"to":        "mirror.example.com/busybox",
+		}))
+	})
+
+	It("prefers mappings over the registry", func() {
+		rewriter := &render.ImageRewriter{
+			Registry: "mirror.example.com",
+			Mappings: map[string]string{
+				"istio/proxyv2":          "mirror.example.com/mesh/proxy",
+				"quay.io/app/server:1.0": "mirror.example.com/server:1.0-patched",
+			},
+		}
+		transformed, err := rewriter.Transform(context.TODO(), render.ValuesInputs{}, resources)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(images(transformed[0], "spec", "template", "spec", "containers")).To(Equal([]string{
+			"mirror.example.com/server:1.0-patched",
+			"mirror.example.com/mesh/proxy@sha256:abc",
+		}))
+	})
+
+	It("matches mappings against normalized images and keeps the images as written otherwise", func() {
+		rewriter := &render.ImageRewriter{
+			Registry: "localhost:5000",
+			Mappings: map[string]string{"docker.io/library/busybox": "mirror.example.com/busybox"},
+		}
+		transformed, err := rewriter.Transform(context.TODO(), render.ValuesInputs{}, resources)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(images(transformed[0], "spec", "template", "spec", "initContainers")).To(Equal([]string{
+			"mirror.example.com/busybox",
+		}))
+		Expect(images(transformed[0], "spec", "template", "spec", "containers")).To(Equal([]string{
+			"localhost:5000/app/server:1.0",
+			"localhost:5000/istio/proxyv2@sha256:abc",
+		}))
+	})
+
+	It("leaves images that are already in the registry untouched", func() {
+		rewriter := &render.ImageRewriter{Registry: "gcr.io"}
+		transformed, err := rewriter.Transform(context.TODO(), render.ValuesInputs{}, resources)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(images(transformed[1], "spec", "jobTemplate", "spec", "template", "spec", "containers")).To(Equal([]string{
+			"gcr.io/project/cleanup:2.0",
+		}))
+	})
+
+	It("extracts the images of workloads and known custom resources", func() {
+		prometheus, err := render.YamlToResources([]byte(`apiVersion: monitoring.coreos.com/v1
+kind: Prometheus
+metadata:
+  name: prom
+  namespace: monitoring
+spec:
+  image: quay.io/prometheus/prometheus:v2.15.2
+  containers:
+  - name: sidecar
+    image: sidecar:1.0
+`))
+		Expect(err).NotTo(HaveOccurred())
+		images, err := render.ExtractImages(append(resources, prometheus...))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(images).To(Equal([]render.ContainerImage{
+			{Kind: "Deployment", Name: "app", Container: "init", Image: "busybox"},
+			{Kind: "Deployment", Name: "app", Container: "app", Image: "quay.io/app/server:1.0"},
+			{Kind: "Deployment", Name: "app", Container: "proxy", Image: "istio/proxyv2@sha256:abc"},
+			{Kind: "CronJob", Name: "cleanup", Container: "cleanup", Image: "gcr.io/project/cleanup:2.0"},
+			{Kind: "Prometheus", Namespace: "monitoring", Name: "prom", Container: "sidecar", Image: "sidecar:1.0"},
+			{Kind: "Prometheus", Namespace: "monitoring", Name: "prom", Container: "spec.image", Image: "quay.io/prometheus/prometheus:v2.15.2"},
+		}))
+	})
+})
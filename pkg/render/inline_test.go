@@ -0,0 +1,65 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
+)
+
+var _ = Describe("inline manifests installation source", func() {
+
+	inputs := render.ValuesInputs{
+		Name:             "app",
+		InstallNamespace: "install",
+		Flavor:           &v1.Flavor{},
+		MeshRef:          core.ResourceRef{Name: "istio", Namespace: "istio-system"},
+	}
+
+	inline := func(yaml string) *v1.VersionedApplicationSpec {
+		return &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_InstallationSteps{
+				InstallationSteps: &v1.InstallationSteps{
+					Steps: []*v1.InstallationSteps_Step{{
+						Name: "glue",
+						Step: &v1.InstallationSteps_Step_InlineManifests{
+							InlineManifests: &v1.InlineManifests{Yaml: yaml},
+						},
+					}},
+				},
+			},
+		}
+	}
+
+	It("templates and renders the embedded manifests", func() {
+		spec := inline(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mesh-config
+  namespace: {{ .InstallNamespace }}
+data:
+  mesh: {{ .MeshRef.Name }}
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+`)
+		manifests, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+		resources, err := manifests.ResourceList()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(HaveLen(2))
+		Expect(resources[0].GetNamespace()).To(Equal("install"))
+		Expect(resources[0].GetLabels()).To(HaveKeyWithValue(render.InstallationStepLabel, "glue"))
+		Expect(manifests.CombinedString()).To(ContainSubstring("mesh: istio"))
+	})
+
+	It("fails on invalid templates", func() {
+		_, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, inline("{{ .Missing "))
+		Expect(err).To(HaveOccurred())
+	})
+})
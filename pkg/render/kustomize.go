@@ -0,0 +1,268 @@
+package render
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/installutils/helmchart"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render/inputs"
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+	"sigs.k8s.io/kustomize/k8sdeps"
+	"sigs.k8s.io/kustomize/pkg/fs"
+	"sigs.k8s.io/kustomize/pkg/loader"
+	"sigs.k8s.io/kustomize/pkg/target"
+)
+
+const (
+	// Kind of the kustomize generators that are rendered as go templates before an overlay is built.
+	ManifestRenderKind = "ManifestRender"
+
+	kustomizationFilename  = "kustomization.yaml"
+	kustomizeBaseDirectory = "base"
+	kustomizeBaseResources = "resources.yaml"
+)
+
+var (
+	MissingKustomizeLocationError = errors.Errorf("missing kustomize overlay location")
+
+	FailedToApplyKustomizeOverlayError = func(err error, overlayPath string) error {
+		return errors.Wrapf(err, "error applying kustomize overlay %v", overlayPath)
+	}
+
+	FailedToRenderManifestRenderError = func(err error, name string) error {
+		return errors.Wrapf(err, "error rendering %v generator %v", ManifestRenderKind, name)
+	}
+)
+
+// A generator whose manifest is a go template rendered with inputs.ManifestRenderValues.
+type manifestRender struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Manifest string `json:"manifest"`
+}
+
+// Applies the kustomize overlays of the selected layer options, in the order in which the layers were selected.
+func ApplyKustomizeLayers(ctx context.Context, fetcher ArtifactFetcher, inputs ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	var overlays []*hubv1.KustomizeOverlay
+	for _, layerInput := range inputs.Layers {
+		option, err := GetLayerOptionFromFlavor(layerInput.LayerId, layerInput.OptionId, inputs.Flavor)
+		if err != nil {
+			return nil, err
+		}
+		if option.GetKustomize() != nil {
+			overlays = append(overlays, option.GetKustomize())
+		}
+	}
+	if len(overlays) == 0 {
+		return resources, nil
+	}
+
+	renderValues, err := getManifestRenderValues(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for _, overlay := range overlays {
+		resources, err = applyKustomizeOverlay(ctx, fetcher, overlay, renderValues, resources)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+func getManifestRenderValues(ctx context.Context, valuesInputs ValuesInputs) (inputs.ManifestRenderValues, error) {
+	values, err := ComputeValueOverrides(ctx, valuesInputs)
+	if err != nil {
+		return inputs.ManifestRenderValues{}, err
+	}
+	custom, err := ConvertYamlStringToNestedMap(values)
+	if err != nil {
+		return inputs.ManifestRenderValues{}, err
+	}
+	return inputs.ManifestRenderValues{
+		Name:             valuesInputs.Name,
+		InstallNamespace: valuesInputs.InstallNamespace,
+		MeshRef:          valuesInputs.MeshRef,
+		Custom:           custom,
+	}, nil
+}
+
+func applyKustomizeOverlay(ctx context.Context, fetcher ArtifactFetcher, overlay *hubv1.KustomizeOverlay, values inputs.ManifestRenderValues, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	switch location := overlay.GetLocation().(type) {
+	case *hubv1.KustomizeOverlay_Github:
+		content, err := fetcher.FetchGithubArchive(ctx, location.Github)
+		if err != nil {
+			wrapped := FailedToApplyKustomizeOverlayError(err, overlay.GetOverlayPath())
+			contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(), zap.Error(err), zap.Any("location", location.Github))
+			return nil, wrapped
+		}
+		afs := afero.NewMemMapFs()
+		dir, err := untarArchive(afs, content)
+		if err != nil {
+			return nil, FailedToApplyKustomizeOverlayError(err, overlay.GetOverlayPath())
+		}
+		repoDir, err := getSingleDirectory(afs, dir)
+		if err != nil {
+			return nil, FailedToApplyKustomizeOverlayError(err, overlay.GetOverlayPath())
+		}
+		return RenderKustomizeOverlay(ctx, afs, filepath.Join(repoDir, location.Github.Directory), overlay.GetOverlayPath(), values, resources)
+	default:
+		return nil, MissingKustomizeLocationError
+	}
+}
+
+// Builds the overlay found at overlayPath inside of dir on top of the given resources.
+// The resources are written to a kustomization in the "base" directory of dir before the overlay is built.
+func RenderKustomizeOverlay(ctx context.Context, afs afero.Fs, dir, overlayPath string, values inputs.ManifestRenderValues, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	contextutils.LoggerFrom(ctx).Infow("Applying kustomize overlay",
+		zap.String("directory", dir),
+		zap.String("overlayPath", overlayPath))
+
+	rendered, err := renderKustomizeOverlay(afs, dir, overlayPath, values, resources)
+	if err != nil {
+		wrapped := FailedToApplyKustomizeOverlayError(err, overlayPath)
+		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+			zap.Error(err),
+			zap.String("directory", dir),
+			zap.String("overlayPath", overlayPath))
+		return nil, wrapped
+	}
+	return rendered, nil
+}
+
+func renderKustomizeOverlay(afs afero.Fs, dir, overlayPath string, values inputs.ManifestRenderValues, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	// Kustomize needs its own file system, so the overlay directory is copied into an in-memory one.
+	const root = "/kustomize"
+	fSys := fs.MakeFakeFS()
+	err := afero.Walk(afs, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := afero.ReadFile(afs, path)
+		if err != nil {
+			return err
+		}
+		return fSys.WriteFile(filepath.Join(root, rel), content)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeKustomizeBase(fSys, filepath.Join(root, kustomizeBaseDirectory), resources); err != nil {
+		return nil, err
+	}
+	overlayDir := filepath.Join(root, overlayPath)
+	if err := renderManifestRenderGenerators(fSys, overlayDir, values); err != nil {
+		return nil, err
+	}
+
+	ldr, err := loader.NewLoader(overlayDir, fSys)
+	if err != nil {
+		return nil, err
+	}
+	defer ldr.Cleanup()
+	factory := k8sdeps.NewFactory()
+	kustTarget, err := target.NewKustTarget(ldr, factory.ResmapF, factory.TransformerF)
+	if err != nil {
+		return nil, err
+	}
+	resMap, err := kustTarget.MakeCustomizedResMap()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := resMap.EncodeAsYaml()
+	if err != nil {
+		return nil, err
+	}
+	return YamlToResources(manifest)
+}
+
+func writeKustomizeBase(fSys fs.FileSystem, baseDir string, resources kuberesource.UnstructuredResources) error {
+	manifests, err := helmchart.ManifestsFromResources(resources)
+	if err != nil {
+		return err
+	}
+	kustomization, err := yaml.Marshal(map[string]interface{}{"resources": []string{kustomizeBaseResources}})
+	if err != nil {
+		return err
+	}
+	if err := fSys.WriteFile(filepath.Join(baseDir, kustomizationFilename), kustomization); err != nil {
+		return err
+	}
+	return fSys.WriteFile(filepath.Join(baseDir, kustomizeBaseResources), []byte(manifests.CombinedString()))
+}
+
+// Kustomize does not know how to run ManifestRender generators, so they are rendered here and the results are
+// added to the resources of the overlay's kustomization.
+func renderManifestRenderGenerators(fSys fs.FileSystem, overlayDir string, values inputs.ManifestRenderValues) error {
+	kustomizationPath := filepath.Join(overlayDir, kustomizationFilename)
+	content, err := fSys.ReadFile(kustomizationPath)
+	if err != nil {
+		return err
+	}
+	kustomization := make(map[string]interface{})
+	if err := yaml.Unmarshal(content, &kustomization); err != nil {
+		return err
+	}
+	generators, ok := kustomization["generators"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	resources, _ := kustomization["resources"].([]interface{})
+	var remainingGenerators []interface{}
+	for _, generator := range generators {
+		generatorFile, ok := generator.(string)
+		if !ok {
+			remainingGenerators = append(remainingGenerators, generator)
+			continue
+		}
+		generatorContent, err := fSys.ReadFile(filepath.Join(overlayDir, generatorFile))
+		if err != nil {
+			return err
+		}
+		var spec manifestRender
+		if err := yaml.Unmarshal(generatorContent, &spec); err != nil {
+			return err
+		}
+		if spec.Kind != ManifestRenderKind {
+			remainingGenerators = append(remainingGenerators, generator)
+			continue
+		}
+
+		rendered, err := execTemplate(spec.Metadata.Name, spec.Manifest, values)
+		if err != nil {
+			return FailedToRenderManifestRenderError(err, spec.Metadata.Name)
+		}
+		renderedFile := spec.Metadata.Name + "-" + filepath.Base(generatorFile)
+		if err := fSys.WriteFile(filepath.Join(overlayDir, renderedFile), []byte(rendered)); err != nil {
+			return err
+		}
+		resources = append(resources, renderedFile)
+	}
+
+	kustomization["resources"] = resources
+	if len(remainingGenerators) > 0 {
+		kustomization["generators"] = remainingGenerators
+	} else {
+		delete(kustomization, "generators")
+	}
+	content, err = yaml.Marshal(kustomization)
+	if err != nil {
+		return err
+	}
+	return fSys.WriteFile(kustomizationPath, content)
+}
@@ -0,0 +1,102 @@
+package render_test
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/service-mesh-hub/pkg/render/inputs"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("kustomize", func() {
+
+	const (
+		dir = "/overlays"
+
+		deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: flagger
+  namespace: istio-system
+`
+		kustomization = `
+bases:
+- ./../base
+
+commonLabels:
+  app: flagger
+
+generators:
+- cluster-role-binding.yaml
+`
+		clusterRoleBinding = `
+kind: ManifestRender
+metadata:
+  name: flagger-supergloo
+manifest: |
+  apiVersion: rbac.authorization.k8s.io/v1
+  kind: ClusterRoleBinding
+  metadata:
+    name: flagger-supergloo
+  roleRef:
+    apiGroup: rbac.authorization.k8s.io
+    kind: ClusterRole
+    name: {{ .Custom.Supergloo.ClusterRoleName }}
+  subjects:
+  - name: {{ .Custom.serviceAccount.name }}
+    namespace: {{ .InstallNamespace }}
+    kind: ServiceAccount
+`
+	)
+
+	var (
+		fs     afero.Fs
+		values inputs.ManifestRenderValues
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		Expect(afero.WriteFile(fs, filepath.Join(dir, "supergloo", "kustomization.yaml"), []byte(kustomization), 0644)).NotTo(HaveOccurred())
+		Expect(afero.WriteFile(fs, filepath.Join(dir, "supergloo", "cluster-role-binding.yaml"), []byte(clusterRoleBinding), 0644)).NotTo(HaveOccurred())
+		values = inputs.ManifestRenderValues{
+			Name:             "flagger",
+			InstallNamespace: "istio-system",
+			Custom: map[string]interface{}{
+				"Supergloo":      map[string]interface{}{"ClusterRoleName": "supergloo-cluster-role"},
+				"serviceAccount": map[string]interface{}{"name": "flagger"},
+			},
+		}
+	})
+
+	It("applies the overlay and its ManifestRender generators on top of the rendered resources", func() {
+		resources, err := render.YamlToResources([]byte(deployment))
+		Expect(err).NotTo(HaveOccurred())
+
+		rendered, err := render.RenderKustomizeOverlay(context.TODO(), fs, dir, "supergloo", values, resources)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).To(HaveLen(2))
+		for _, resource := range rendered {
+			Expect(resource.GetLabels()).To(HaveKeyWithValue("app", "flagger"))
+		}
+
+		crb := rendered.Filter(func(resource *unstructured.Unstructured) bool {
+			return resource.GetKind() != "ClusterRoleBinding"
+		})
+		Expect(crb).To(HaveLen(1))
+		roleName, _, _ := unstructured.NestedString(crb[0].Object, "roleRef", "name")
+		Expect(roleName).To(Equal("supergloo-cluster-role"))
+		subjects, _, _ := unstructured.NestedSlice(crb[0].Object, "subjects")
+		Expect(subjects).To(ConsistOf(HaveKeyWithValue("namespace", "istio-system")))
+	})
+
+	It("errors when the overlay does not exist", func() {
+		_, err := render.RenderKustomizeOverlay(context.TODO(), fs, dir, "missing", values, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("error applying kustomize overlay missing"))
+	})
+})
@@ -0,0 +1,86 @@
+package render_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+)
+
+var _ = Describe("local installation sources", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "local-source-")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(name, content string) {
+		filename := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(filename), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filename, []byte(content), 0644)).To(Succeed())
+	}
+
+	inputs := render.ValuesInputs{
+		Name:             "app",
+		InstallNamespace: "install",
+		Flavor:           &v1.Flavor{},
+	}
+
+	It("renders a local chart directory", func() {
+		writeFile("chart/Chart.yaml", "apiVersion: v1\nname: app\nversion: 0.1.0\n")
+		writeFile("chart/values.yaml", "replicas: 1\n")
+		writeFile("chart/templates/configmap.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  replicas: "{{ .Values.replicas }}"
+`)
+		spec := &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_LocalChart{
+				LocalChart: &v1.LocalLocation{Path: filepath.Join(dir, "chart")},
+			},
+		}
+		manifests, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests.CombinedString()).To(ContainSubstring("name: app-config"))
+		Expect(manifests.CombinedString()).To(ContainSubstring("namespace: install"))
+	})
+
+	It("reads and templates a local manifests directory", func() {
+		writeFile("manifests/configmap.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: {{ .InstallNamespace }}
+`)
+		spec := &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_InstallationSteps{
+				InstallationSteps: &v1.InstallationSteps{
+					Steps: []*v1.InstallationSteps_Step{{
+						Name: "manifests",
+						Step: &v1.InstallationSteps_Step_LocalManifests{
+							LocalManifests: &v1.LocalLocation{Path: filepath.Join(dir, "manifests"), Template: true},
+						},
+					}},
+				},
+			},
+		}
+		manifests, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests.CombinedString()).To(ContainSubstring("namespace: install"))
+	})
+})
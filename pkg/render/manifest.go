@@ -1,16 +1,20 @@
 package render
 
 import (
-	"bytes"
 	"context"
-	"text/template"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/solo-io/service-mesh-hub/pkg/render/validation"
 	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
 
 	errors "github.com/rotisserie/eris"
 	"github.com/solo-io/go-utils/contextutils"
-	"github.com/solo-io/go-utils/installutils"
 	"github.com/solo-io/go-utils/installutils/helmchart"
 	"github.com/solo-io/go-utils/installutils/kuberesource"
 	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
@@ -28,8 +32,12 @@ var (
 		return errors.Wrapf(err, "error converting manifests to raw resources")
 	}
 
-	FailedRenderValueTemplatesError = func(err error) error {
-		return errors.Wrapf(err, "error rendering input value templates")
+	FailedRenderValueTemplatesError = func(err error, field string) error {
+		return errors.Wrapf(err, "error rendering input value templates in %v", field)
+	}
+
+	FailedRenderManifestTemplatesError = func(err error) error {
+		return errors.Wrapf(err, "error rendering manifest templates")
 	}
 
 	MissingInputForRequiredLayer = func(err error) error {
@@ -45,6 +53,30 @@ var (
 	}
 
 	IncorrectNumberOfInputLayersError = errors.Errorf("incorrect number of input layers")
+
+	InvalidParamValueError = func(name string, paramType hubv1.ParameterType, value string) error {
+		return errors.Errorf("Parameter %v must be of type %v, found %q", name, paramType, value)
+	}
+
+	ParamValueNotAllowedError = func(name, value string, allowedValues []string) error {
+		return errors.Errorf("Parameter %v must be one of %v, found %q", name, strings.Join(allowedValues, ", "), value)
+	}
+
+	InvalidParamPatternError = func(err error, name string) error {
+		return errors.Wrapf(err, "invalid pattern for parameter %v", name)
+	}
+
+	ParamValueMismatchesPatternError = func(name, value, pattern string) error {
+		return errors.Errorf("Parameter %v must match %q, found %q", name, pattern, value)
+	}
+
+	ParamValueOutOfRangeError = func(name, value, bound string) error {
+		return errors.Errorf("Parameter %v must be %v, found %v", name, bound, value)
+	}
+
+	ParamValueLengthOutOfRangeError = func(name, value, bound string) error {
+		return errors.Errorf("Parameter %v must be %v characters long, found %q", name, bound, value)
+	}
 )
 
 type SuperglooInfo struct {
@@ -68,6 +100,14 @@ type ValuesInputs struct {
 	SpecDefinedValues string
 	// These map to the params found on versions, flavors, and layers,
 	Params map[string]string
+	// Names of the params of type SECRET, whose values are redacted from logs and errors. The renderer adds the ones
+	// declared by the spec.
+	SecretParams []string
+
+	// Version of the target cluster, i.e. v1.17.2. Helm's default is used if empty.
+	KubeVersion string
+	// API versions available on the target cluster in addition to helm's defaults, i.e. monitoring.coreos.com/v1
+	ApiVersions []string
 }
 
 // Deprecated: use ManifestRenderer.ComputeResourcesForApplication
@@ -107,119 +147,359 @@ func ValidateInputs(inputs ValuesInputs, spec hubv1.VersionedApplicationSpec, va
 	}
 
 	// Validate parameters.
-	allParameters := make(map[string]*hubv1.Parameter)
+	allParameters := getDeclaredParams(&spec, inputs.Flavor, selectedOptions)
+	for _, param := range allParameters {
+		if value := inputs.Params[param.Name]; param.Required && value == "" {
+			return MissingInputForRequireParam(param.Name)
+		}
+	}
+	for name, value := range inputs.Params {
+		param, ok := allParameters[name]
+		if !ok {
+			return UnrecognizedParamError(name)
+		}
+		// Templates are validated once rendered, see ValidateParamTypes.
+		if !IsParamTemplate(value) {
+			if err := ValidateParamConstraints(param, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the names of the required params declared for the inputs' flavor and layer options that the inputs have no
+// value for, sorted.
+func GetMissingRequiredParams(inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec) []string {
+	var missing []string
+	for name, param := range getDeclaredParams(spec, inputs.Flavor, getSelectedOptions(inputs)) {
+		if param.Required && inputs.Params[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Returns the parameters declared by the spec, the flavor and the layer options, by name.
+func getDeclaredParams(spec *hubv1.VersionedApplicationSpec, flavor *hubv1.Flavor, options []*hubv1.LayerOption) map[string]*hubv1.Parameter {
+	params := make(map[string]*hubv1.Parameter)
 	for _, param := range spec.GetParameters() {
-		allParameters[param.Name] = param
+		params[param.Name] = param
 	}
-	for _, param := range inputs.Flavor.GetParameters() {
-		allParameters[param.Name] = param
+	for _, param := range flavor.GetParameters() {
+		params[param.Name] = param
 	}
-	for _, option := range selectedOptions {
-		for _, param := range option.Parameters {
-			allParameters[param.Name] = param
+	for _, option := range options {
+		for _, param := range option.GetParameters() {
+			params[param.Name] = param
 		}
 	}
-	for _, param := range allParameters {
-		if value := inputs.Params[param.Name]; param.Required && value == "" {
-			return MissingInputForRequireParam(param.Name)
+	return params
+}
+
+// Returns the layer options selected by the inputs, skipping unknown ones.
+func getSelectedOptions(inputs ValuesInputs) []*hubv1.LayerOption {
+	var options []*hubv1.LayerOption
+	for _, layer := range inputs.Layers {
+		if option, err := GetLayerOptionFromFlavor(layer.LayerId, layer.OptionId, inputs.Flavor); err == nil {
+			options = append(options, option)
 		}
 	}
+	return options
+}
+
+// Validates the value of every param against the type and constraints it is declared with. Params are validated once
+// their templates are rendered, see ExecInputValuesTemplates.
+func ValidateParamTypes(inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec) error {
+	params := getDeclaredParams(spec, inputs.Flavor, getSelectedOptions(inputs))
+	names := make([]string, 0, len(inputs.Params))
 	for name := range inputs.Params {
-		if _, ok := allParameters[name]; !ok {
-			return UnrecognizedParamError(name)
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if param, ok := params[name]; ok {
+			if err := ValidateParamValue(param, inputs.Params[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Layouts accepted for DATE params: RFC 3339, plain dates, and the format of the default values of DATE params.
+var paramDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+}
+
+// Validates a value against the type and constraints of the param. Empty values are left to the check of required
+// params.
+func ValidateParamValue(param *hubv1.Parameter, value string) error {
+	if value == "" {
+		return nil
+	}
+	var err error
+	switch param.GetType() {
+	case hubv1.ParameterType_INT:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case hubv1.ParameterType_FLOAT:
+		_, err = strconv.ParseFloat(value, 64)
+	case hubv1.ParameterType_BOOL:
+		// Only these are turned into booleans by helm's strvals.
+		if !strings.EqualFold(value, "true") && !strings.EqualFold(value, "false") {
+			err = InvalidParamValueError(param.GetName(), param.GetType(), value)
+		}
+	case hubv1.ParameterType_DATE:
+		err = InvalidParamValueError(param.GetName(), param.GetType(), value)
+		for _, layout := range paramDateLayouts {
+			if _, parseErr := time.Parse(layout, value); parseErr == nil {
+				err = nil
+				break
+			}
+		}
+	}
+	if err != nil {
+		return InvalidParamValueError(param.GetName(), param.GetType(), value)
+	}
+	return ValidateParamConstraints(param, value)
+}
+
+// Validates a value against the allowed values, pattern, range and length declared on the param. Empty values are left
+// to the check of required params.
+func ValidateParamConstraints(param *hubv1.Parameter, value string) error {
+	if value == "" {
+		return nil
+	}
+	name := param.GetName()
+	// The values of secret params are left out of the errors.
+	shown := value
+	if param.GetType() == hubv1.ParameterType_SECRET {
+		shown = RedactedValue
+	}
+
+	if allowedValues := param.GetAllowedValues(); len(allowedValues) > 0 {
+		allowed := false
+		for _, allowedValue := range allowedValues {
+			if value == allowedValue {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ParamValueNotAllowedError(name, shown, allowedValues)
 		}
 	}
 
+	if pattern := param.GetPattern(); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return InvalidParamPatternError(err, name)
+		}
+		if !re.MatchString(value) {
+			return ParamValueMismatchesPatternError(name, shown, pattern)
+		}
+	}
+
+	// Values that are not numbers are left to the check of the type, see ValidateParamValue.
+	isNumeric := param.GetType() == hubv1.ParameterType_INT || param.GetType() == hubv1.ParameterType_FLOAT
+	if number, err := strconv.ParseFloat(value, 64); isNumeric && err == nil {
+		if minimum := param.GetMinimum(); minimum != nil && number < minimum.GetValue() {
+			return ParamValueOutOfRangeError(name, shown, fmt.Sprintf("at least %v", minimum.GetValue()))
+		}
+		if maximum := param.GetMaximum(); maximum != nil && number > maximum.GetValue() {
+			return ParamValueOutOfRangeError(name, shown, fmt.Sprintf("at most %v", maximum.GetValue()))
+		}
+	}
+
+	length := uint32(utf8.RuneCountInString(value))
+	if minLength := param.GetMinLength(); minLength != nil && length < minLength.GetValue() {
+		return ParamValueLengthOutOfRangeError(name, shown, fmt.Sprintf("at least %v", minLength.GetValue()))
+	}
+	if maxLength := param.GetMaxLength(); maxLength != nil && length > maxLength.GetValue() {
+		return ParamValueLengthOutOfRangeError(name, shown, fmt.Sprintf("at most %v", maxLength.GetValue()))
+	}
 	return nil
 }
 
+// Returns whether the value of a param is a template, which can only be validated once it is rendered.
+func IsParamTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
 /*
- Coalesces spec values yaml, layer values, params, and user-defined values yaml.
- User defined values override params which override layer values which override spec values.
- If there is an error parsing, it is logged and propagated.
+Coalesces spec values yaml, layer values, params, and user-defined values yaml.
+Layer values are rendered as templates, with inputs as the data, before they are coalesced.
+User defined values override params which override layer values which override spec values.
+If there is an error parsing, it is logged and propagated, with the values of secret params redacted.
 */
 func ComputeValueOverrides(ctx context.Context, inputs ValuesInputs) (string, error) {
+	redactor := newRedactor(inputs)
+	values, err := computeValueOverrides(withRedactedLogger(ctx, redactor), redactor, inputs)
+	return values, redactor.Error(err)
+}
+
+func computeValueOverrides(ctx context.Context, redactor *redactor, inputs ValuesInputs) (string, error) {
+	sources, err := getValueSources(ctx, inputs)
+	if err != nil {
+		return "", err
+	}
 	valuesMap := make(map[string]interface{})
+	for _, source := range sources {
+		valuesMap = CoalesceValuesMap(ctx, valuesMap, source.values)
+	}
+
+	values, err := ConvertNestedMapToYaml(valuesMap)
+	if err != nil {
+		contextutils.LoggerFrom(ctx).Errorw(err.Error(), zap.Error(err), zap.Any("valuesMap", redactor.ValuesMap(valuesMap)))
+		return "", err
+	}
+	return values, nil
+}
+
+// Values parsed from one of the sources coalesced by ComputeValueOverrides.
+type sourceValues struct {
+	source ValueSource
+	values map[string]interface{}
+}
+
+// Parses the values of each source, in increasing order of precedence: spec values, layer values, params by name, and
+// user values.
+func getValueSources(ctx context.Context, inputs ValuesInputs) ([]sourceValues, error) {
+	var sources []sourceValues
 
 	specValues, err := ConvertYamlStringToNestedMap(inputs.SpecDefinedValues)
 	if err != nil {
 		contextutils.LoggerFrom(ctx).Errorw("Error parsing spec values yaml",
 			zap.Error(err),
 			zap.String("values", inputs.SpecDefinedValues))
-		return "", err
+		return nil, err
 	}
-	valuesMap = CoalesceValuesMap(ctx, valuesMap, specValues)
+	sources = append(sources, sourceValues{source: ValueSource{Type: SpecValuesSource}, values: specValues})
 
 	for _, layerInput := range inputs.Layers {
 		option, err := GetLayerOptionFromFlavor(layerInput.LayerId, layerInput.OptionId, inputs.Flavor)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
 		if option.HelmValues != "" {
-			layerValues, err := ConvertYamlStringToNestedMap(option.HelmValues)
+			// Layer values are templated with the same data as the other values, so they can reference params.
+			helmValues, err := execTemplate(layerInput.LayerId+"/"+layerInput.OptionId, option.HelmValues, inputs)
+			if err != nil {
+				return nil, FailedRenderValueTemplatesError(err, "layer option "+layerInput.LayerId+"/"+layerInput.OptionId)
+			}
+			layerValues, err := ConvertYamlStringToNestedMap(helmValues)
 			if err != nil {
 				contextutils.LoggerFrom(ctx).Errorw("Error parsing layer values yaml",
 					zap.Error(err),
-					zap.String("values", option.HelmValues))
-				return "", err
+					zap.String("values", helmValues))
+				return nil, err
 			}
-			valuesMap = CoalesceValuesMap(ctx, valuesMap, layerValues)
+			source := ValueSource{Type: LayerValuesSource, Name: layerInput.LayerId + "/" + layerInput.OptionId}
+			sources = append(sources, sourceValues{source: source, values: layerValues})
 		}
 	}
 
-	paramValues, err := ConvertParamsToNestedMap(inputs.Params)
-	if err != nil {
-		contextutils.LoggerFrom(ctx).Errorw("Error parsing install params",
-			zap.Error(err))
-		return "", err
+	names := make([]string, 0, len(inputs.Params))
+	for name := range inputs.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		paramValues := make(map[string]interface{})
+		if err := parseParamInto(paramValues, name, inputs.Params[name], isSecretParam(inputs, name)); err != nil {
+			contextutils.LoggerFrom(ctx).Errorw("Error parsing install params",
+				zap.Error(err))
+			return nil, err
+		}
+		sources = append(sources, sourceValues{source: ValueSource{Type: ParamSource, Name: name}, values: paramValues})
 	}
-	valuesMap = CoalesceValuesMap(ctx, valuesMap, paramValues)
 
 	userValues, err := ConvertYamlStringToNestedMap(inputs.UserDefinedValues)
 	if err != nil {
 		contextutils.LoggerFrom(ctx).Errorw("Error parsing user values yaml",
 			zap.Error(err),
 			zap.Any("params", inputs.UserDefinedValues))
-		return "", err
-	}
-	valuesMap = CoalesceValuesMap(ctx, valuesMap, userValues)
-
-	values, err := ConvertNestedMapToYaml(valuesMap)
-	if err != nil {
-		contextutils.LoggerFrom(ctx).Errorw(err.Error(), zap.Error(err), zap.Any("valuesMap", valuesMap))
-		return "", err
+		return nil, err
 	}
-	return values, nil
+	sources = append(sources, sourceValues{source: ValueSource{Type: UserValuesSource}, values: userValues})
+	return sources, nil
 }
 
+// Fetches the artifacts referenced by the spec from their remote locations, then renders them into manifests.
 func GetManifestsFromApplicationSpec(ctx context.Context, inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec) (helmchart.Manifests, error) {
+	redactor := newRedactor(inputs)
+	manifests, err := getManifestsFromApplicationSpec(withRedactedLogger(ctx, redactor), NewRemoteArtifactFetcher(), inputs, spec)
+	return manifests, redactor.Error(err)
+}
+
+func getManifestsFromApplicationSpec(ctx context.Context, fetcher ArtifactFetcher, inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec) (helmchart.Manifests, error) {
 	var manifests helmchart.Manifests
 	switch installationSpec := spec.GetInstallationSpec().(type) {
 	case *hubv1.VersionedApplicationSpec_GithubChart:
-		githubManifests, err := getManifestsFromGithub(ctx, installationSpec.GithubChart, inputs)
+		githubManifests, err := getManifestsFromGithub(ctx, fetcher, installationSpec.GithubChart, inputs)
 		if err != nil {
 			return nil, err
 		}
 		manifests = githubManifests
 	case *hubv1.VersionedApplicationSpec_HelmArchive:
-		helmManifests, err := getManifestsFromHelm(ctx, installationSpec.HelmArchive, inputs)
+		helmManifests, err := getManifestsFromHelm(ctx, fetcher, installationSpec.HelmArchive, inputs)
 		if err != nil {
 			return nil, err
 		}
 		manifests = helmManifests
 	case *hubv1.VersionedApplicationSpec_ManifestsArchive:
-		archiveManifests, err := getManifestsFromArchive(ctx, installationSpec.ManifestsArchive, inputs)
+		archiveManifests, err := getManifestsFromArchive(ctx, fetcher, installationSpec.ManifestsArchive, inputs)
 		if err != nil {
 			return nil, err
 		}
 		manifests = archiveManifests
 	case *hubv1.VersionedApplicationSpec_InstallationSteps:
-		archiveManifests, err := getManifestsFromSteps(ctx, installationSpec.InstallationSteps, inputs)
+		archiveManifests, err := getManifestsFromSteps(ctx, fetcher, installationSpec.InstallationSteps, inputs)
 		if err != nil {
 			return nil, err
 		}
 		manifests = archiveManifests
+	case *hubv1.VersionedApplicationSpec_LocalChart:
+		localManifests, err := getManifestsFromLocalChart(ctx, fetcher, installationSpec.LocalChart, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = localManifests
+	case *hubv1.VersionedApplicationSpec_LocalManifests:
+		localManifests, err := getManifestsFromLocalDirectory(ctx, fetcher, installationSpec.LocalManifests, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = localManifests
+	case *hubv1.VersionedApplicationSpec_HelmRepository:
+		helmManifests, err := getManifestsFromHelmRepository(ctx, fetcher, installationSpec.HelmRepository, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = helmManifests
+	case *hubv1.VersionedApplicationSpec_OciChart:
+		ociManifests, err := getManifestsFromOciChart(ctx, fetcher, installationSpec.OciChart, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = ociManifests
+	case *hubv1.VersionedApplicationSpec_GitChart:
+		gitManifests, err := getManifestsFromGit(ctx, fetcher, installationSpec.GitChart, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = gitManifests
+	case *hubv1.VersionedApplicationSpec_InlineManifests:
+		inlineManifests, err := getManifestsFromInlineManifests(ctx, installationSpec.InlineManifests, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = inlineManifests
 	default:
 		return nil, MissingInstallSpecError
 	}
@@ -236,33 +516,108 @@ func FilterByLabel(ctx context.Context, spec *hubv1.VersionedApplicationSpec, re
 	return resources
 }
 
-func getManifestsFromHelm(ctx context.Context, helmInstallSpec *hubv1.TgzLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
+func getManifestsFromHelm(ctx context.Context, fetcher ArtifactFetcher, helmInstallSpec *hubv1.TgzLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
 	values, err := ComputeValueOverrides(ctx, inputs)
 	if err != nil {
 		return nil, err
 	}
-	contextutils.LoggerFrom(ctx).Infow("Rendering with values", zap.String("values", values))
-	manifests, err := helmchart.RenderManifests(ctx,
-		helmInstallSpec.Uri,
-		values,
-		inputs.Name,
-		inputs.InstallNamespace,
-		"")
+	contextutils.LoggerFrom(ctx).Infow("Rendering with values", zap.String("values", newRedactor(inputs).Values(values)))
+	manifests, err := renderChartArchive(ctx, fetcher, helmInstallSpec.Uri, values, inputs)
 	if err != nil {
 		wrapped := FailedToRenderManifestsError(err)
 		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
 			zap.Error(err),
 			zap.String("chartUri", helmInstallSpec.Uri),
-			zap.String("values", values),
+			zap.String("values", newRedactor(inputs).Values(values)),
 			zap.String("releaseName", inputs.Name),
 			zap.String("namespace", inputs.InstallNamespace),
-			zap.String("kubeVersion", ""))
+			zap.String("kubeVersion", inputs.KubeVersion),
+			zap.Strings("apiVersions", inputs.ApiVersions))
 		return nil, wrapped
 	}
 	return manifests, nil
 }
 
-func getManifestsFromGithub(ctx context.Context, githubInstallSpec *hubv1.GithubRepositoryLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
+func renderChartArchive(ctx context.Context, fetcher ArtifactFetcher, uri, values string, inputs ValuesInputs) (helmchart.Manifests, error) {
+	content, err := fetcher.FetchArchive(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadChartArchive(content)
+	if err != nil {
+		return nil, err
+	}
+	return RenderChart(ctx, chart, values, inputs)
+}
+
+func getManifestsFromHelmRepository(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.HelmRepositoryLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
+	values, err := ComputeValueOverrides(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := renderChartFromHelmRepository(ctx, fetcher, location, values, inputs)
+	if err != nil {
+		wrapped := FailedToRenderManifestsError(err)
+		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+			zap.Error(err),
+			zap.Any("location", location),
+			zap.String("values", newRedactor(inputs).Values(values)),
+			zap.String("releaseName", inputs.Name),
+			zap.String("namespace", inputs.InstallNamespace),
+			zap.String("kubeVersion", inputs.KubeVersion),
+			zap.Strings("apiVersions", inputs.ApiVersions))
+		return nil, wrapped
+	}
+	return manifests, nil
+}
+
+func renderChartFromHelmRepository(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.HelmRepositoryLocation, values string, inputs ValuesInputs) (helmchart.Manifests, error) {
+	content, err := FetchHelmRepositoryChart(ctx, fetcher, location)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadChartArchive(content)
+	if err != nil {
+		return nil, err
+	}
+	return RenderChart(ctx, chart, values, inputs)
+}
+
+func getManifestsFromOciChart(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.OciChartLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
+	values, err := ComputeValueOverrides(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := renderOciChart(ctx, fetcher, location, values, inputs)
+	if err != nil {
+		wrapped := FailedToRenderManifestsError(err)
+		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+			zap.Error(err),
+			zap.String("reference", location.GetReference()),
+			zap.String("digest", location.GetDigest()),
+			zap.String("values", newRedactor(inputs).Values(values)),
+			zap.String("releaseName", inputs.Name),
+			zap.String("namespace", inputs.InstallNamespace),
+			zap.String("kubeVersion", inputs.KubeVersion),
+			zap.Strings("apiVersions", inputs.ApiVersions))
+		return nil, wrapped
+	}
+	return manifests, nil
+}
+
+func renderOciChart(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.OciChartLocation, values string, inputs ValuesInputs) (helmchart.Manifests, error) {
+	content, err := fetcher.FetchOciChart(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadChartArchive(content)
+	if err != nil {
+		return nil, err
+	}
+	return RenderChart(ctx, chart, values, inputs)
+}
+
+func getManifestsFromGithub(ctx context.Context, fetcher ArtifactFetcher, githubInstallSpec *hubv1.GithubRepositoryLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
 	ref := helmchart.GithubChartRef{
 		Owner:          githubInstallSpec.Org,
 		Repo:           githubInstallSpec.Repo,
@@ -273,27 +628,69 @@ func getManifestsFromGithub(ctx context.Context, githubInstallSpec *hubv1.Github
 	if err != nil {
 		return nil, err
 	}
-	manifests, err := helmchart.RenderManifestsFromGithub(ctx, ref,
-		values,
-		inputs.Name,
-		inputs.InstallNamespace,
-		"")
+	manifests, err := renderChartFromGithub(ctx, fetcher, githubInstallSpec, values, inputs)
 	if err != nil {
 		wrapped := FailedToRenderManifestsError(err)
 		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
 			zap.Error(err),
 			zap.Any("ref", ref),
-			zap.String("values", values),
+			zap.String("values", newRedactor(inputs).Values(values)),
+			zap.String("releaseName", inputs.Name),
+			zap.String("namespace", inputs.InstallNamespace),
+			zap.String("kubeVersion", inputs.KubeVersion),
+			zap.Strings("apiVersions", inputs.ApiVersions))
+		return nil, wrapped
+	}
+	return manifests, nil
+}
+
+func renderChartFromGithub(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.GithubRepositoryLocation, values string, inputs ValuesInputs) (helmchart.Manifests, error) {
+	content, err := fetcher.FetchGithubArchive(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadChartFromRepositoryArchive(content, location.Directory)
+	if err != nil {
+		return nil, err
+	}
+	return RenderChart(ctx, chart, values, inputs)
+}
+
+func getManifestsFromGit(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.GitRepositoryLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
+	values, err := ComputeValueOverrides(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := renderChartFromGit(ctx, fetcher, location, values, inputs)
+	if err != nil {
+		wrapped := FailedToRenderManifestsError(err)
+		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+			zap.Error(err),
+			zap.Any("location", location),
+			zap.String("values", newRedactor(inputs).Values(values)),
 			zap.String("releaseName", inputs.Name),
 			zap.String("namespace", inputs.InstallNamespace),
-			zap.String("kubeVersion", ""))
+			zap.String("kubeVersion", inputs.KubeVersion),
+			zap.Strings("apiVersions", inputs.ApiVersions))
 		return nil, wrapped
 	}
 	return manifests, nil
 }
 
-func getManifestsFromArchive(ctx context.Context, manifestsArchive *hubv1.TgzLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
-	manifests, err := installutils.GetManifestsFromRemoteTar(manifestsArchive.GetUri())
+func renderChartFromGit(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.GitRepositoryLocation, values string, inputs ValuesInputs) (helmchart.Manifests, error) {
+	content, err := fetcher.FetchGitArchive(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadChartFromRepositoryArchive(content, location.GetDirectory())
+	if err != nil {
+		return nil, err
+	}
+	return RenderChart(ctx, chart, values, inputs)
+}
+
+func getManifestsFromArchive(ctx context.Context, fetcher ArtifactFetcher, manifestsArchive *hubv1.TgzLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
+	manifests, err := getManifestsFromRemoteArchive(ctx, fetcher, manifestsArchive.GetUri())
 	if err != nil {
 		wrapped := FailedToRenderManifestsError(err)
 		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
@@ -303,12 +700,128 @@ func getManifestsFromArchive(ctx context.Context, manifestsArchive *hubv1.TgzLoc
 			zap.String("namespace", inputs.InstallNamespace))
 		return nil, wrapped
 	}
+	if manifestsArchive.GetTemplate() {
+		if manifests, err = ExecManifestTemplates(manifests, inputs); err != nil {
+			wrapped := FailedRenderManifestTemplatesError(err)
+			contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+				zap.Error(err),
+				zap.String("manifestsArchiveUrl", manifestsArchive.GetUri()))
+			return nil, wrapped
+		}
+	}
 	return manifests, nil
 }
 
+func getManifestsFromRemoteArchive(ctx context.Context, fetcher ArtifactFetcher, uri string) (helmchart.Manifests, error) {
+	content, err := fetcher.FetchArchive(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	return GetManifestsFromArchiveContent(content)
+}
+
+const inlineManifestsName = "inline-manifests.yaml"
+
+func getManifestsFromInlineManifests(ctx context.Context, inline *hubv1.InlineManifests, inputs ValuesInputs) (helmchart.Manifests, error) {
+	templates := helmchart.Manifests{{Name: inlineManifestsName, Content: inline.GetYaml()}}
+	rendered, err := ExecManifestTemplates(templates, inputs)
+	if err != nil {
+		wrapped := FailedRenderManifestTemplatesError(err)
+		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(), zap.Error(err))
+		return nil, wrapped
+	}
+	resources, err := YamlToResources([]byte(rendered[0].Content))
+	if err != nil {
+		wrapped := FailedToRenderManifestsError(err)
+		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+			zap.Error(err),
+			zap.String("manifests", rendered[0].Content))
+		return nil, wrapped
+	}
+	return helmchart.ManifestsFromResources(resources)
+}
+
+func getManifestsFromLocalChart(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.LocalLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
+	values, err := ComputeValueOverrides(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := renderLocalChart(ctx, fetcher, location.GetPath(), values, inputs)
+	if err != nil {
+		wrapped := FailedToRenderManifestsError(err)
+		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+			zap.Error(err),
+			zap.String("chartPath", location.GetPath()),
+			zap.String("values", newRedactor(inputs).Values(values)),
+			zap.String("releaseName", inputs.Name),
+			zap.String("namespace", inputs.InstallNamespace),
+			zap.String("kubeVersion", inputs.KubeVersion),
+			zap.Strings("apiVersions", inputs.ApiVersions))
+		return nil, wrapped
+	}
+	return manifests, nil
+}
+
+func renderLocalChart(ctx context.Context, fetcher ArtifactFetcher, path, values string, inputs ValuesInputs) (helmchart.Manifests, error) {
+	content, err := fetcher.FetchLocalDirectory(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadChartFromDirectoryArchive(content)
+	if err != nil {
+		return nil, err
+	}
+	return RenderChart(ctx, chart, values, inputs)
+}
+
+func getManifestsFromLocalDirectory(ctx context.Context, fetcher ArtifactFetcher, location *hubv1.LocalLocation, inputs ValuesInputs) (helmchart.Manifests, error) {
+	manifests, err := readLocalManifests(ctx, fetcher, location.GetPath())
+	if err != nil {
+		wrapped := FailedToRenderManifestsError(err)
+		contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+			zap.Error(err),
+			zap.String("manifestsPath", location.GetPath()),
+			zap.String("releaseName", inputs.Name),
+			zap.String("namespace", inputs.InstallNamespace))
+		return nil, wrapped
+	}
+	if location.GetTemplate() {
+		if manifests, err = ExecManifestTemplates(manifests, inputs); err != nil {
+			wrapped := FailedRenderManifestTemplatesError(err)
+			contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(),
+				zap.Error(err),
+				zap.String("manifestsPath", location.GetPath()))
+			return nil, wrapped
+		}
+	}
+	return manifests, nil
+}
+
+func readLocalManifests(ctx context.Context, fetcher ArtifactFetcher, path string) (helmchart.Manifests, error) {
+	content, err := fetcher.FetchLocalDirectory(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return GetManifestsFromArchiveContent(content)
+}
+
+// Renders the content of each manifest as a go template, using 'inputs' as the template data.
+func ExecManifestTemplates(manifests helmchart.Manifests, inputs ValuesInputs) (helmchart.Manifests, error) {
+	rendered := make(helmchart.Manifests, 0, len(manifests))
+	for _, manifest := range manifests {
+		content, err := execTemplate(manifest.Name, manifest.Content, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Content = content
+		rendered = append(rendered, manifest)
+	}
+	return rendered, nil
+}
+
 const InstallationStepLabel = "service-mesh-hub.solo.io/installation_step"
 
-func getManifestsFromSteps(ctx context.Context, steps *hubv1.InstallationSteps, inputs ValuesInputs) (helmchart.Manifests, error) {
+func getManifestsFromSteps(ctx context.Context, fetcher ArtifactFetcher, steps *hubv1.InstallationSteps, inputs ValuesInputs) (helmchart.Manifests, error) {
 	if len(steps.Steps) == 0 {
 		return nil, errors.Errorf("must provide at least one installation step")
 	}
@@ -325,7 +838,7 @@ func getManifestsFromSteps(ctx context.Context, steps *hubv1.InstallationSteps,
 		}
 		uniqueStepNames = append(uniqueStepNames, step.Name)
 
-		manifests, err := getManifestsFromInstallationStep(ctx, inputs, step)
+		manifests, err := getManifestsFromInstallationStep(ctx, fetcher, inputs, step)
 		if err != nil {
 			return nil, err
 		}
@@ -353,27 +866,63 @@ func getManifestsFromSteps(ctx context.Context, steps *hubv1.InstallationSteps,
 	return combinedManifests, nil
 }
 
-func getManifestsFromInstallationStep(ctx context.Context, inputs ValuesInputs, step *hubv1.InstallationSteps_Step) (helmchart.Manifests, error) {
+func getManifestsFromInstallationStep(ctx context.Context, fetcher ArtifactFetcher, inputs ValuesInputs, step *hubv1.InstallationSteps_Step) (helmchart.Manifests, error) {
 	var manifests helmchart.Manifests
 	switch installationSpec := step.Step.(type) {
 	case *hubv1.InstallationSteps_Step_GithubChart:
-		githubManifests, err := getManifestsFromGithub(ctx, installationSpec.GithubChart, inputs)
+		githubManifests, err := getManifestsFromGithub(ctx, fetcher, installationSpec.GithubChart, inputs)
 		if err != nil {
 			return nil, err
 		}
 		manifests = githubManifests
 	case *hubv1.InstallationSteps_Step_HelmArchive:
-		helmManifests, err := getManifestsFromHelm(ctx, installationSpec.HelmArchive, inputs)
+		helmManifests, err := getManifestsFromHelm(ctx, fetcher, installationSpec.HelmArchive, inputs)
 		if err != nil {
 			return nil, err
 		}
 		manifests = helmManifests
 	case *hubv1.InstallationSteps_Step_ManifestsArchive:
-		archiveManifests, err := getManifestsFromArchive(ctx, installationSpec.ManifestsArchive, inputs)
+		archiveManifests, err := getManifestsFromArchive(ctx, fetcher, installationSpec.ManifestsArchive, inputs)
 		if err != nil {
 			return nil, err
 		}
 		manifests = archiveManifests
+	case *hubv1.InstallationSteps_Step_LocalChart:
+		localManifests, err := getManifestsFromLocalChart(ctx, fetcher, installationSpec.LocalChart, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = localManifests
+	case *hubv1.InstallationSteps_Step_LocalManifests:
+		localManifests, err := getManifestsFromLocalDirectory(ctx, fetcher, installationSpec.LocalManifests, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = localManifests
+	case *hubv1.InstallationSteps_Step_HelmRepository:
+		helmManifests, err := getManifestsFromHelmRepository(ctx, fetcher, installationSpec.HelmRepository, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = helmManifests
+	case *hubv1.InstallationSteps_Step_OciChart:
+		ociManifests, err := getManifestsFromOciChart(ctx, fetcher, installationSpec.OciChart, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = ociManifests
+	case *hubv1.InstallationSteps_Step_GitChart:
+		gitManifests, err := getManifestsFromGit(ctx, fetcher, installationSpec.GitChart, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = gitManifests
+	case *hubv1.InstallationSteps_Step_InlineManifests:
+		inlineManifests, err := getManifestsFromInlineManifests(ctx, installationSpec.InlineManifests, inputs)
+		if err != nil {
+			return nil, err
+		}
+		manifests = inlineManifests
 	default:
 		return nil, MissingInstallSpecError
 	}
@@ -384,34 +933,43 @@ func getManifestsFromInstallationStep(ctx context.Context, inputs ValuesInputs,
 // The SpecDefinedValues, UserDefinedValues, and Params inputs can contain template
 // actions (text delimited by "{{" and "}}" ). This function renders the contents of these
 // parameters using the data contained in 'input' and updates 'input' with the results.
+// Params are rendered first, after the params they reference, so every template sees the rendered params.
 func ExecInputValuesTemplates(inputs ValuesInputs) (ValuesInputs, error) {
 
-	// Render the helm values string that comes from the extension spec
-	buf := new(bytes.Buffer)
-	tpl := template.Must(template.New("specValues").Parse(inputs.SpecDefinedValues))
-	if err := tpl.Execute(buf, inputs); err != nil {
+	// Render the values of the parameters, in dependency order
+	paramNames, err := orderParams(inputs.Params)
+	if err != nil {
 		return ValuesInputs{}, err
 	}
-	inputs.SpecDefinedValues = buf.String()
-	buf.Reset()
+	if inputs.Params != nil {
+		// Copy the params rather than rendering them in the caller's map
+		params := make(map[string]string, len(inputs.Params))
+		for name, value := range inputs.Params {
+			params[name] = value
+		}
+		inputs.Params = params
+	}
+	for _, paramName := range paramNames {
+		paramValue, err := execTemplate(paramName, inputs.Params[paramName], inputs)
+		if err != nil {
+			return ValuesInputs{}, FailedRenderValueTemplatesError(err, "parameter "+paramName)
+		}
+		inputs.Params[paramName] = paramValue
+	}
 
-	// Render the helm values string that comes from the user provided overrides
-	tpl = template.Must(template.New("userValues").Parse(inputs.UserDefinedValues))
-	if err := tpl.Execute(buf, inputs); err != nil {
-		return ValuesInputs{}, err
+	// Render the helm values string that comes from the extension spec
+	specValues, err := execTemplate("specValues", inputs.SpecDefinedValues, inputs)
+	if err != nil {
+		return ValuesInputs{}, FailedRenderValueTemplatesError(err, "spec values")
 	}
-	inputs.UserDefinedValues = buf.String()
-	buf.Reset()
 
-	// Render the values of the parameters
-	for paramName, paramValue := range inputs.Params {
-		t := template.Must(template.New(paramName).Parse(paramValue))
-		if err := t.Execute(buf, inputs); err != nil {
-			return ValuesInputs{}, err
-		}
-		inputs.Params[paramName] = buf.String()
-		buf.Reset()
+	// Render the helm values string that comes from the user provided overrides
+	userValues, err := execTemplate("userValues", inputs.UserDefinedValues, inputs)
+	if err != nil {
+		return ValuesInputs{}, FailedRenderValueTemplatesError(err, "user values")
 	}
 
+	inputs.SpecDefinedValues = specValues
+	inputs.UserDefinedValues = userValues
 	return inputs, nil
 }
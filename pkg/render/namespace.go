@@ -0,0 +1,165 @@
+package render
+
+import (
+	"context"
+
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Kinds of the built-in kubernetes resources that are not namespaced.
+var ClusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"CertificateSigningRequest":      true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"ComponentStatus":                true,
+	"CSIDriver":                      true,
+	"CSINode":                        true,
+	"CustomResourceDefinition":       true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"Node":                           true,
+	"PersistentVolume":               true,
+	"PodSecurityPolicy":              true,
+	"PriorityClass":                  true,
+	"RuntimeClass":                   true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+	"VolumeAttachment":               true,
+}
+
+// Kinds of the built-in kubernetes resources that are namespaced.
+var NamespacedKinds = map[string]bool{
+	"Binding":                 true,
+	"ConfigMap":               true,
+	"ControllerRevision":      true,
+	"CronJob":                 true,
+	"DaemonSet":               true,
+	"Deployment":              true,
+	"Endpoints":               true,
+	"EndpointSlice":           true,
+	"Event":                   true,
+	"HorizontalPodAutoscaler": true,
+	"Ingress":                 true,
+	"Job":                     true,
+	"Lease":                   true,
+	"LimitRange":              true,
+	"NetworkPolicy":           true,
+	"PersistentVolumeClaim":   true,
+	"Pod":                     true,
+	"PodDisruptionBudget":     true,
+	"PodTemplate":             true,
+	"ReplicaSet":              true,
+	"ReplicationController":   true,
+	"ResourceQuota":           true,
+	"Role":                    true,
+	"RoleBinding":             true,
+	"Secret":                  true,
+	"Service":                 true,
+	"ServiceAccount":          true,
+	"StatefulSet":             true,
+}
+
+// Moves every namespaced resource into the install namespace, unless the spec asks to respect the namespaces
+// in the manifests. References to the namespaces that resources were moved out of are rewritten as well, so that
+// role bindings and webhook configurations keep pointing at the moved service accounts and services.
+// Resources of kinds that are neither built in nor defined by a CRD in the manifest, e.g. custom resources whose CRD is
+// installed separately, are only moved if they already have a namespace, since they may be cluster scoped.
+func ApplyInstallNamespace(ctx context.Context, inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec, resources kuberesource.UnstructuredResources) kuberesource.UnstructuredResources {
+	if spec.GetRespectManifestNamespaces() || inputs.InstallNamespace == "" {
+		return resources
+	}
+	contextutils.LoggerFrom(ctx).Infow("Moving namespaced resources to the install namespace",
+		zap.String("namespace", inputs.InstallNamespace))
+
+	namespacedKinds := getNamespacedKinds(resources)
+	movedFrom := make(map[string]bool)
+	for _, resource := range resources {
+		namespaced, known := namespacedKinds[resource.GetKind()]
+		if !known {
+			namespaced = resource.GetNamespace() != ""
+		}
+		if !namespaced {
+			if !known {
+				contextutils.LoggerFrom(ctx).Debugw("Leaving resource of unknown scope in place",
+					zap.String("kind", resource.GetKind()),
+					zap.String("name", resource.GetName()))
+			}
+			continue
+		}
+		if namespace := resource.GetNamespace(); namespace != inputs.InstallNamespace {
+			// References without a namespace are left alone, they do not necessarily point at the moved resources.
+			if namespace != "" {
+				movedFrom[namespace] = true
+			}
+			resource.SetNamespace(inputs.InstallNamespace)
+		}
+	}
+
+	for _, resource := range resources {
+		switch resource.GetKind() {
+		case "RoleBinding", "ClusterRoleBinding":
+			rewriteNamespaceReferences(resource, inputs.InstallNamespace, movedFrom, "subjects")
+		case "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration":
+			rewriteNamespaceReferences(resource, inputs.InstallNamespace, movedFrom, "webhooks", "clientConfig", "service")
+		case "APIService":
+			rewriteNamespaceReference(resource.Object, inputs.InstallNamespace, movedFrom, "spec", "service")
+		}
+	}
+	return resources
+}
+
+// Whether each kind is namespaced, for the built-in kinds and the kinds of the custom resources defined in the manifest.
+// Kinds of unknown scope are missing.
+func getNamespacedKinds(resources kuberesource.UnstructuredResources) map[string]bool {
+	kinds := make(map[string]bool, len(ClusterScopedKinds)+len(NamespacedKinds))
+	for kind := range ClusterScopedKinds {
+		kinds[kind] = false
+	}
+	for kind := range NamespacedKinds {
+		kinds[kind] = true
+	}
+	for _, resource := range resources {
+		if resource.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		scope, _, _ := unstructured.NestedString(resource.Object, "spec", "scope")
+		kind, _, _ := unstructured.NestedString(resource.Object, "spec", "names", "kind")
+		if kind != "" {
+			kinds[kind] = scope != "Cluster"
+		}
+	}
+	return kinds
+}
+
+// Rewrites the namespace of each reference found in the list at listField, at the path within the list item.
+func rewriteNamespaceReferences(resource *unstructured.Unstructured, namespace string, movedFrom map[string]bool, listField string, path ...string) {
+	items, found, err := unstructured.NestedSlice(resource.Object, listField)
+	if !found || err != nil {
+		return
+	}
+	for _, item := range items {
+		if itemMap, ok := item.(map[string]interface{}); ok {
+			// Only service accounts carry a namespace among role binding subjects.
+			if kind, ok := itemMap["kind"]; listField == "subjects" && ok && kind != "ServiceAccount" {
+				continue
+			}
+			rewriteNamespaceReference(itemMap, namespace, movedFrom, path...)
+		}
+	}
+	_ = unstructured.SetNestedSlice(resource.Object, items, listField)
+}
+
+func rewriteNamespaceReference(obj map[string]interface{}, namespace string, movedFrom map[string]bool, path ...string) {
+	reference, found, err := unstructured.NestedMap(obj, path...)
+	if !found || err != nil {
+		return
+	}
+	if current, ok := reference["namespace"].(string); ok && movedFrom[current] {
+		_ = unstructured.SetNestedField(obj, namespace, append(path, "namespace")...)
+	}
+}
@@ -0,0 +1,135 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("install namespace", func() {
+
+	const manifest = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: operator
+  namespace: hardcoded
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: operator
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: operator
+subjects:
+- kind: ServiceAccount
+  name: operator
+  namespace: hardcoded
+- kind: ServiceAccount
+  name: other
+  namespace: kube-system
+- kind: ServiceAccount
+  name: unqualified
+  namespace: ""
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: operator
+webhooks:
+- name: operator.example.io
+  clientConfig:
+    service:
+      name: operator
+      namespace: hardcoded
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: clusterwidgets.example.io
+spec:
+  scope: Cluster
+  names:
+    kind: ClusterWidget
+---
+apiVersion: example.io/v1
+kind: ClusterWidget
+metadata:
+  name: widget
+---
+apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: issuer
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: routes
+  namespace: hardcoded
+`
+
+	var (
+		resources kuberesource.UnstructuredResources
+		inputs    render.ValuesInputs
+	)
+
+	BeforeEach(func() {
+		var err error
+		resources, err = render.YamlToResources([]byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+		inputs = render.ValuesInputs{InstallNamespace: "install"}
+	})
+
+	find := func(resources kuberesource.UnstructuredResources, kind string) *unstructured.Unstructured {
+		for _, resource := range resources {
+			if resource.GetKind() == kind {
+				return resource
+			}
+		}
+		return nil
+	}
+
+	It("moves namespaced resources and rewrites references to them", func() {
+		result := render.ApplyInstallNamespace(context.TODO(), inputs, &v1.VersionedApplicationSpec{}, resources)
+		Expect(find(result, "ServiceAccount").GetNamespace()).To(Equal("install"))
+		Expect(find(result, "ConfigMap").GetNamespace()).To(Equal("install"))
+		Expect(find(result, "ClusterRole").GetNamespace()).To(BeEmpty())
+		Expect(find(result, "ClusterWidget").GetNamespace()).To(BeEmpty())
+
+		subjects, _, _ := unstructured.NestedSlice(find(result, "ClusterRoleBinding").Object, "subjects")
+		Expect(subjects[0]).To(HaveKeyWithValue("namespace", "install"))
+		Expect(subjects[1]).To(HaveKeyWithValue("namespace", "kube-system"))
+		Expect(subjects[2]).To(HaveKeyWithValue("namespace", ""))
+
+		webhooks, _, _ := unstructured.NestedSlice(find(result, "ValidatingWebhookConfiguration").Object, "webhooks")
+		namespace, _, _ := unstructured.NestedString(webhooks[0].(map[string]interface{}), "clientConfig", "service", "namespace")
+		Expect(namespace).To(Equal("install"))
+	})
+
+	It("only moves resources of unknown kinds that have a namespace", func() {
+		result := render.ApplyInstallNamespace(context.TODO(), inputs, &v1.VersionedApplicationSpec{}, resources)
+		Expect(find(result, "ClusterIssuer").GetNamespace()).To(BeEmpty())
+		Expect(find(result, "VirtualService").GetNamespace()).To(Equal("install"))
+	})
+
+	It("respects manifest namespaces when the spec asks to", func() {
+		spec := &v1.VersionedApplicationSpec{RespectManifestNamespaces: true}
+		result := render.ApplyInstallNamespace(context.TODO(), inputs, spec, resources)
+		Expect(find(result, "ServiceAccount").GetNamespace()).To(Equal("hardcoded"))
+		Expect(find(result, "ConfigMap").GetNamespace()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,340 @@
+package render
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"go.uber.org/zap"
+)
+
+const (
+	// Prefix of references to artifacts in a local OCI image layout directory.
+	OciLayoutScheme = "oci-layout://"
+
+	ociDefaultTag           = "latest"
+	ociRefNameAnnotation    = "org.opencontainers.image.ref.name"
+	ociIndexFilename        = "index.json"
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// Only sha256 digests are supported, which are also the only ones used as file names in image layouts.
+var ociDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Media types of the layer holding the chart archive, as pushed by the different versions of helm.
+var helmChartLayerMediaTypes = map[string]bool{
+	"application/tar+gzip":                                true,
+	"application/vnd.cncf.helm.chart.content.v1.tar+gzip": true,
+}
+
+var (
+	InvalidOciReferenceError = func(reference string) error {
+		return errors.Errorf("invalid OCI reference %v", reference)
+	}
+
+	InvalidOciDigestError = func(digest string) error {
+		return errors.Errorf("invalid OCI digest %q: must be sha256 followed by 64 hex digits", digest)
+	}
+
+	OciDigestMismatchError = func(reference, expected, actual string) error {
+		return errors.Errorf("digest of %v does not match: expected %v, found %v", reference, expected, actual)
+	}
+
+	MissingChartLayerError = func(reference string) error {
+		return errors.Errorf("OCI artifact %v does not contain a helm chart layer", reference)
+	}
+
+	OciTagNotFoundError = func(tag, layoutPath string) error {
+		return errors.Errorf("tag %v not found in OCI image layout %v", tag, layoutPath)
+	}
+
+	FailedToPullOciContentError = func(status, url string) error {
+		return errors.Errorf("unexpected status %v pulling %v", status, url)
+	}
+)
+
+// A parsed reference to an OCI artifact, either in a registry or in a local image layout.
+type OciReference struct {
+	// Set for references to artifacts in a local image layout
+	LayoutPath string
+	// Host (and port) of the registry
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// Parses the reference of an OCI chart location. The location's digest takes precedence over a digest in the reference.
+func ParseOciReference(location *hubv1.OciChartLocation) (*OciReference, error) {
+	reference := location.GetReference()
+	ref := &OciReference{}
+	isLayout := strings.HasPrefix(reference, OciLayoutScheme)
+	reference = strings.TrimPrefix(reference, OciLayoutScheme)
+
+	if i := strings.Index(reference, "@"); i >= 0 {
+		ref.Digest = reference[i+1:]
+		reference = reference[:i]
+	}
+	// A colon after the last slash separates the tag, anything before it may be a registry port.
+	if i := strings.LastIndex(reference, ":"); i > strings.LastIndex(reference, "/") {
+		ref.Tag = reference[i+1:]
+		reference = reference[:i]
+	}
+	if location.GetDigest() != "" {
+		ref.Digest = location.GetDigest()
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = ociDefaultTag
+	}
+
+	if isLayout {
+		ref.LayoutPath = reference
+		return ref, nil
+	}
+	parts := strings.SplitN(reference, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, InvalidOciReferenceError(location.GetReference())
+	}
+	ref.Registry, ref.Repository = parts[0], parts[1]
+	return ref, nil
+}
+
+func (r *OciReference) String() string {
+	var s string
+	if r.LayoutPath != "" {
+		s = OciLayoutScheme + r.LayoutPath
+	} else {
+		s = r.Registry + "/" + r.Repository
+	}
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	Config ociDescriptor   `json:"config"`
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// Content-addressed storage for OCI manifests and blobs.
+type ociStore interface {
+	// Returns the manifest for the reference's digest, or for its tag if it has no digest.
+	Manifest(ctx context.Context, ref *OciReference) ([]byte, error)
+	// Returns the blob with the digest, verified against it.
+	Blob(ctx context.Context, ref *OciReference, digest string) ([]byte, error)
+}
+
+// Returns the chart archive stored as the OCI artifact, verifying the manifest and the chart layer against their
+// digests.
+func FetchOciChartArchive(ctx context.Context, location *hubv1.OciChartLocation) ([]byte, error) {
+	ref, err := ParseOciReference(location)
+	if err != nil {
+		return nil, err
+	}
+	return fetchOciChartArchive(ctx, ref, newOciStore(ref))
+}
+
+func newOciStore(ref *OciReference) ociStore {
+	if ref.LayoutPath != "" {
+		return &ociLayoutStore{}
+	}
+	return newOciRegistryStore()
+}
+
+func fetchOciChartArchive(ctx context.Context, ref *OciReference, store ociStore) ([]byte, error) {
+	contextutils.LoggerFrom(ctx).Infow("Pulling OCI chart", zap.String("reference", ref.String()))
+	manifestContent, err := store.Manifest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Digest != "" {
+		if err := verifyOciDigest(ref.String(), ref.Digest, manifestContent); err != nil {
+			return nil, err
+		}
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, err
+	}
+	for _, layer := range manifest.Layers {
+		if !helmChartLayerMediaTypes[layer.MediaType] {
+			continue
+		}
+		return store.Blob(ctx, ref, layer.Digest)
+	}
+	return nil, MissingChartLayerError(ref.String())
+}
+
+// Digests are read from manifests and indexes, so they are checked before being used in urls or file paths.
+func validateOciDigest(digest string) error {
+	if !ociDigestPattern.MatchString(digest) {
+		return InvalidOciDigestError(digest)
+	}
+	return nil
+}
+
+func verifyOciDigest(reference, expected string, content []byte) error {
+	sum := sha256.Sum256(content)
+	actual := "sha256:" + hex.EncodeToString(sum[:])
+	if actual != expected {
+		return OciDigestMismatchError(reference, expected, actual)
+	}
+	return nil
+}
+
+// Reads artifacts from an OCI image layout directory, see https://github.com/opencontainers/image-spec/blob/master/image-layout.md
+type ociLayoutStore struct{}
+
+func (s *ociLayoutStore) Manifest(ctx context.Context, ref *OciReference) ([]byte, error) {
+	if ref.Digest != "" {
+		return s.Blob(ctx, ref, ref.Digest)
+	}
+	indexContent, err := ioutil.ReadFile(filepath.Join(ref.LayoutPath, ociIndexFilename))
+	if err != nil {
+		return nil, err
+	}
+	var index ociIndex
+	if err := json.Unmarshal(indexContent, &index); err != nil {
+		return nil, err
+	}
+	for _, manifest := range index.Manifests {
+		if manifest.Annotations[ociRefNameAnnotation] == ref.Tag {
+			return s.Blob(ctx, ref, manifest.Digest)
+		}
+	}
+	return nil, OciTagNotFoundError(ref.Tag, ref.LayoutPath)
+}
+
+func (s *ociLayoutStore) Blob(_ context.Context, ref *OciReference, digest string) ([]byte, error) {
+	if err := validateOciDigest(digest); err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(filepath.Join(ref.LayoutPath, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:")))
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyOciDigest(ref.String(), digest, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// Pulls artifacts from a registry implementing the OCI distribution api, authenticating with the credentials stored by
+// docker login when the registry asks for them.
+type ociRegistryStore struct {
+	client      *http.Client
+	credentials func(registry string) (*RegistryCredentials, error)
+	// Authorization headers by repository, so that the registry's challenge is answered once.
+	authorizations map[string]string
+}
+
+func newOciRegistryStore() *ociRegistryStore {
+	return &ociRegistryStore{
+		client:         http.DefaultClient,
+		credentials:    GetDockerCredentials,
+		authorizations: make(map[string]string),
+	}
+}
+
+func (s *ociRegistryStore) Manifest(ctx context.Context, ref *OciReference) ([]byte, error) {
+	reference := ref.Digest
+	if reference == "" {
+		reference = ref.Tag
+	} else if err := validateOciDigest(reference); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, ref, "manifests/"+reference, ociManifestMediaType+", "+dockerManifestMediaType)
+}
+
+func (s *ociRegistryStore) Blob(ctx context.Context, ref *OciReference, digest string) ([]byte, error) {
+	if err := validateOciDigest(digest); err != nil {
+		return nil, err
+	}
+	content, err := s.get(ctx, ref, "blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyOciDigest(ref.String(), digest, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (s *ociRegistryStore) get(ctx context.Context, ref *OciReference, path, accept string) ([]byte, error) {
+	host := registryHost(ref.Registry)
+	url := fmt.Sprintf("%v://%v/v2/%v/%v", registryScheme(host), host, ref.Repository, path)
+	repository := ref.Registry + "/" + ref.Repository
+	resp, err := s.do(ctx, url, accept, s.authorizations[repository])
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		authorization, err := s.authorize(ctx, ref, challenge)
+		if err != nil {
+			return nil, err
+		}
+		s.authorizations[repository] = authorization
+		if resp, err = s.do(ctx, url, accept, authorization); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, FailedToPullOciContentError(resp.Status, url)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (s *ociRegistryStore) do(ctx context.Context, url, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return s.client.Do(req)
+}
+
+// Like docker, registries on the local host are reached over plain http.
+func registryScheme(registry string) string {
+	host := registry
+	if i := strings.LastIndex(registry, ":"); i >= 0 {
+		host = registry[:i]
+	}
+	if host == "localhost" || host == "127.0.0.1" {
+		return "http"
+	}
+	return "https"
+}
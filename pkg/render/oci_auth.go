@@ -0,0 +1,329 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	"go.uber.org/zap"
+)
+
+const (
+	dockerConfigEnv      = "DOCKER_CONFIG"
+	dockerConfigFilename = "config.json"
+	// The key docker stores the credentials of Docker Hub under.
+	dockerHubConfigKey = "https://index.docker.io/v1/"
+	dockerHubRegistry  = "registry-1.docker.io"
+	// The username credential helpers return along with identity tokens.
+	dockerIdentityTokenUsername = "<token>"
+	ociTokenClientId            = "hubctl"
+)
+
+var (
+	FailedToReadDockerConfigError = func(err error, path string) error {
+		return errors.Wrapf(err, "failed to read docker config %v", path)
+	}
+
+	FailedToGetDockerCredentialsError = func(err error, helper, registry string) error {
+		return errors.Wrapf(err, "failed to get credentials for %v from docker-credential-%v", registry, helper)
+	}
+
+	OciAuthenticationRequiredError = func(registry string) error {
+		return errors.Errorf("registry %v requires credentials, log in with docker login or helm registry login", registry)
+	}
+
+	UnsupportedOciAuthChallengeError = func(registry, challenge string) error {
+		return errors.Errorf("unsupported authentication challenge %q from registry %v", challenge, registry)
+	}
+
+	FailedToGetOciTokenError = func(realm, status string) error {
+		return errors.Errorf("failed to get a token from %v: %v", realm, status)
+	}
+)
+
+// Credentials for a registry, as stored by docker login or helm registry login.
+type RegistryCredentials struct {
+	Username string
+	Password string
+	// A refresh token to exchange for access tokens, stored instead of a password for some registries.
+	IdentityToken string
+}
+
+type dockerConfig struct {
+	Auths       map[string]dockerAuthConfig `json:"auths"`
+	CredsStore  string                      `json:"credsStore"`
+	CredHelpers map[string]string           `json:"credHelpers"`
+}
+
+type dockerAuthConfig struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// Returns the credentials for the registry stored in the docker config, $DOCKER_CONFIG/config.json or
+// ~/.docker/config.json, either inline or in a credential helper. Returns nil if there are none.
+func GetDockerCredentials(registry string) (*RegistryCredentials, error) {
+	dir := os.Getenv(dockerConfigEnv)
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	path := filepath.Join(dir, dockerConfigFilename)
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, FailedToReadDockerConfigError(err, path)
+	}
+	var config dockerConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, FailedToReadDockerConfigError(err, path)
+	}
+
+	key := dockerConfigKey(registry)
+	for configKey, auth := range config.Auths {
+		if dockerConfigKey(configKey) != key {
+			continue
+		}
+		if credentials, err := auth.credentials(); err != nil || credentials != nil {
+			return credentials, err
+		}
+	}
+	helper := config.CredsStore
+	for configKey, registryHelper := range config.CredHelpers {
+		if dockerConfigKey(configKey) == key {
+			helper = registryHelper
+		}
+	}
+	if helper == "" {
+		return nil, nil
+	}
+	serverUrl := registry
+	if key == dockerConfigKey(dockerHubConfigKey) {
+		serverUrl = dockerHubConfigKey
+	}
+	return getHelperCredentials(helper, serverUrl)
+}
+
+func (a dockerAuthConfig) credentials() (*RegistryCredentials, error) {
+	credentials := &RegistryCredentials{Username: a.Username, Password: a.Password, IdentityToken: a.IdentityToken}
+	if a.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+		if err != nil {
+			return nil, err
+		}
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) == 2 {
+			credentials.Username, credentials.Password = parts[0], parts[1]
+		}
+	}
+	if credentials.Username == "" && credentials.IdentityToken == "" {
+		// Only a placeholder, the credentials are kept by a helper.
+		return nil, nil
+	}
+	return credentials, nil
+}
+
+// Runs the docker credential helper like docker does, see https://github.com/docker/docker-credential-helpers
+func getHelperCredentials(helper, serverUrl string) (*RegistryCredentials, error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverUrl)
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(stdout.String()+stderr.String(), "credentials not found") {
+			return nil, nil
+		}
+		return nil, FailedToGetDockerCredentialsError(errors.Wrap(err, strings.TrimSpace(stderr.String())), helper, serverUrl)
+	}
+	var output struct {
+		Username string
+		Secret   string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, FailedToGetDockerCredentialsError(err, helper, serverUrl)
+	}
+	if output.Username == dockerIdentityTokenUsername {
+		return &RegistryCredentials{IdentityToken: output.Secret}, nil
+	}
+	return &RegistryCredentials{Username: output.Username, Password: output.Secret}, nil
+}
+
+// Reduces the keys of the docker config, which may be urls, to the registry host. Docker Hub goes by several names.
+func dockerConfigKey(key string) string {
+	key = strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+	if i := strings.Index(key, "/"); i >= 0 {
+		key = key[:i]
+	}
+	switch key {
+	case "docker.io", "index.docker.io", dockerHubRegistry:
+		return "docker.io"
+	}
+	return key
+}
+
+// Docker Hub's api is not served under the name its images are referenced by.
+func registryHost(registry string) string {
+	if registry == "docker.io" {
+		return dockerHubRegistry
+	}
+	return registry
+}
+
+// Answers the registry's authentication challenge, see https://docs.docker.com/registry/spec/auth/token/.
+// Returns the value of the Authorization header to retry the request with.
+func (s *ociRegistryStore) authorize(ctx context.Context, ref *OciReference, challenge string) (string, error) {
+	scheme, params := parseAuthChallenge(challenge)
+	credentials, err := s.credentials(ref.Registry)
+	if err != nil {
+		// Public repositories can still be pulled anonymously, e.g. when a credential helper is not installed.
+		contextutils.LoggerFrom(ctx).Warnw("Failed to get registry credentials, pulling anonymously",
+			zap.Error(err),
+			zap.String("registry", ref.Registry))
+		credentials = nil
+	}
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credentials == nil || credentials.Username == "" {
+			return "", OciAuthenticationRequiredError(ref.Registry)
+		}
+		return "Basic " + basicAuth(credentials.Username, credentials.Password), nil
+	case "bearer":
+		scope := params["scope"]
+		if scope == "" {
+			scope = "repository:" + ref.Repository + ":pull"
+		}
+		token, err := s.getToken(ctx, params["realm"], params["service"], scope, credentials)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	}
+	return "", UnsupportedOciAuthChallengeError(ref.Registry, challenge)
+}
+
+// Gets an access token from the realm, anonymously if there are no credentials.
+func (s *ociRegistryStore) getToken(ctx context.Context, realm, service, scope string, credentials *RegistryCredentials) (string, error) {
+	if realm == "" {
+		return "", FailedToGetOciTokenError(realm, "no realm in the challenge")
+	}
+	contextutils.LoggerFrom(ctx).Debugw("Getting OCI registry token",
+		zap.String("realm", realm),
+		zap.String("service", service),
+		zap.String("scope", scope))
+
+	var req *http.Request
+	var err error
+	if credentials != nil && credentials.IdentityToken != "" {
+		form := url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {credentials.IdentityToken},
+			"service":       {service},
+			"scope":         {scope},
+			"client_id":     {ociTokenClientId},
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, realm, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, realm, nil)
+		if err != nil {
+			return "", err
+		}
+		query := req.URL.Query()
+		if service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", scope)
+		req.URL.RawQuery = query.Encode()
+		if credentials != nil && credentials.Username != "" {
+			req.SetBasicAuth(credentials.Username, credentials.Password)
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", FailedToGetOciTokenError(realm, resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", FailedToGetOciTokenError(realm, "no token in the response")
+}
+
+func basicAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// Splits a WWW-Authenticate header, e.g. `Bearer realm="https://auth.example.com/token",service="example"`, into its
+// scheme and parameters.
+func parseAuthChallenge(header string) (string, map[string]string) {
+	header = strings.TrimSpace(header)
+	scheme, rest := header, ""
+	if i := strings.Index(header, " "); i >= 0 {
+		scheme, rest = header[:i], header[i+1:]
+	}
+	params := make(map[string]string)
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		i := strings.Index(rest, "=")
+		if i < 0 {
+			return scheme, params
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:i]))
+		rest = rest[i+1:]
+		var value strings.Builder
+		if strings.HasPrefix(rest, `"`) {
+			j := 1
+			for ; j < len(rest) && rest[j] != '"'; j++ {
+				if rest[j] == '\\' && j+1 < len(rest) {
+					j++
+				}
+				value.WriteByte(rest[j])
+			}
+			if j < len(rest) {
+				j++
+			}
+			rest = rest[j:]
+		} else {
+			j := strings.IndexAny(rest, ", ")
+			if j < 0 {
+				j = len(rest)
+			}
+			value.WriteString(rest[:j])
+			rest = rest[j:]
+		}
+		params[key] = value.String()
+	}
+}
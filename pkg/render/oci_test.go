@@ -0,0 +1,342 @@
+package render_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/cache"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+)
+
+var _ = Describe("OCI chart installation source", func() {
+
+	var (
+		layoutDir      string
+		manifestDigest string
+	)
+
+	writeBlob := func(content []byte) string {
+		sum := sha256.Sum256(content)
+		digest := hex.EncodeToString(sum[:])
+		Expect(ioutil.WriteFile(filepath.Join(layoutDir, "blobs", "sha256", digest), content, 0644)).To(Succeed())
+		return "sha256:" + digest
+	}
+
+	mustJson := func(v interface{}) []byte {
+		content, err := json.Marshal(v)
+		Expect(err).NotTo(HaveOccurred())
+		return content
+	}
+
+	BeforeEach(func() {
+		var err error
+		layoutDir, err = ioutil.TempDir("", "oci-layout-")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(layoutDir, "blobs", "sha256"), 0755)).To(Succeed())
+
+		chart := mustTgz(map[string]string{
+			"app/Chart.yaml": "apiVersion: v1\nname: app\nversion: 1.0.0\n",
+			"app/templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+`,
+		})
+		config := []byte(`{"name":"app","version":"1.0.0"}`)
+		manifest := mustJson(map[string]interface{}{
+			"schemaVersion": 2,
+			"config": map[string]interface{}{
+				"mediaType": "application/vnd.cncf.helm.config.v1+json",
+				"digest":    writeBlob(config),
+				"size":      len(config),
+			},
+			"layers": []map[string]interface{}{{
+				"mediaType": "application/tar+gzip",
+				"digest":    writeBlob(chart),
+				"size":      len(chart),
+			}},
+		})
+		manifestDigest = writeBlob(manifest)
+		index := mustJson(map[string]interface{}{
+			"schemaVersion": 2,
+			"manifests": []map[string]interface{}{{
+				"mediaType":   "application/vnd.oci.image.manifest.v1+json",
+				"digest":      manifestDigest,
+				"size":        len(manifest),
+				"annotations": map[string]string{"org.opencontainers.image.ref.name": "1.0.0"},
+			}},
+		})
+		Expect(ioutil.WriteFile(filepath.Join(layoutDir, "index.json"), index, 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(layoutDir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(layoutDir)
+	})
+
+	inputs := render.ValuesInputs{
+		Name:             "app",
+		InstallNamespace: "install",
+		Flavor:           &v1.Flavor{},
+	}
+
+	spec := func(reference, digest string) *v1.VersionedApplicationSpec {
+		return &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_OciChart{
+				OciChart: &v1.OciChartLocation{Reference: reference, Digest: digest},
+			},
+		}
+	}
+
+	It("parses references", func() {
+		ref, err := render.ParseOciReference(&v1.OciChartLocation{Reference: "localhost:5000/charts/app:1.0.0"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*ref).To(Equal(render.OciReference{Registry: "localhost:5000", Repository: "charts/app", Tag: "1.0.0"}))
+
+		ref, err = render.ParseOciReference(&v1.OciChartLocation{Reference: "oci-layout:///charts/app@sha256:abc"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*ref).To(Equal(render.OciReference{LayoutPath: "/charts/app", Digest: "sha256:abc"}))
+
+		_, err = render.ParseOciReference(&v1.OciChartLocation{Reference: "app"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("renders a chart from a local image layout", func() {
+		manifests, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec(render.OciLayoutScheme+layoutDir+":1.0.0", manifestDigest))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests.CombinedString()).To(ContainSubstring("name: app-config"))
+	})
+
+	It("rejects artifacts that do not match the digest", func() {
+		otherDigest := "sha256:" + strings.Repeat("0", 64)
+		_, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec(render.OciLayoutScheme+layoutDir+":1.0.0", otherDigest))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects digests that are not sha256 digests before reading them", func() {
+		outside := filepath.Join(filepath.Dir(layoutDir), "outside.json")
+		Expect(ioutil.WriteFile(outside, mustJson(map[string]interface{}{"layers": []interface{}{}}), 0644)).To(Succeed())
+		defer os.Remove(outside)
+		index := mustJson(map[string]interface{}{
+			"schemaVersion": 2,
+			"manifests": []map[string]interface{}{{
+				"digest":      "sha256:../../../" + filepath.Base(outside),
+				"annotations": map[string]string{"org.opencontainers.image.ref.name": "1.0.0"},
+			}},
+		})
+		Expect(ioutil.WriteFile(filepath.Join(layoutDir, "index.json"), index, 0644)).To(Succeed())
+
+		_, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec(render.OciLayoutScheme+layoutDir+":1.0.0", ""))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid OCI digest"))
+	})
+
+	It("rejects blobs whose content does not match their digest", func() {
+		blobs, err := ioutil.ReadDir(filepath.Join(layoutDir, "blobs", "sha256"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, blob := range blobs {
+			path := filepath.Join(layoutDir, "blobs", "sha256", blob.Name())
+			if blob.Name() != strings.TrimPrefix(manifestDigest, "sha256:") {
+				Expect(ioutil.WriteFile(path, []byte("tampered"), 0644)).To(Succeed())
+			}
+		}
+
+		_, err = render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec(render.OciLayoutScheme+layoutDir+":1.0.0", ""))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match"))
+	})
+
+	It("caches charts pinned to a digest and verifies them whenever they are read", func() {
+		dir, err := ioutil.TempDir("", "oci-cache-")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		fetcher := render.NewCachingArtifactFetcher(cache.NewArtifactCache(dir), render.NewRemoteArtifactFetcher())
+		location := &v1.OciChartLocation{Reference: render.OciLayoutScheme + layoutDir + ":1.0.0", Digest: manifestDigest}
+
+		chart, err := fetcher.FetchOciChart(context.TODO(), location)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Served from the cache once the layout is gone.
+		Expect(os.RemoveAll(filepath.Join(layoutDir, "blobs"))).To(Succeed())
+		content, err := fetcher.FetchOciChart(context.TODO(), location)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal(chart))
+
+		cached, err := ioutil.ReadDir(filepath.Join(dir, "blobs"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, blob := range cached {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "blobs", blob.Name()), []byte("tampered"), 0644)).To(Succeed())
+		}
+		_, err = fetcher.FetchOciChart(context.TODO(), location)
+		Expect(err).To(HaveOccurred())
+	})
+
+	// A registry stand-in serving the content of the image layout, once authorized.
+	registryHandler := func(authorized func(r *http.Request) bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !authorized(r) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			path := strings.TrimPrefix(r.URL.Path, "/v2/charts/app/")
+			var content []byte
+			var err error
+			switch {
+			case path == "manifests/1.0.0":
+				content, err = ioutil.ReadFile(filepath.Join(layoutDir, "blobs", "sha256", strings.TrimPrefix(manifestDigest, "sha256:")))
+			case strings.HasPrefix(path, "blobs/sha256:"):
+				content, err = ioutil.ReadFile(filepath.Join(layoutDir, "blobs", "sha256", strings.TrimPrefix(path, "blobs/sha256:")))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(content)
+		}
+	}
+
+	It("renders a chart from a registry", func() {
+		server := httptest.NewServer(registryHandler(func(*http.Request) bool { return true }))
+		defer server.Close()
+
+		reference := strings.TrimPrefix(server.URL, "http://") + "/charts/app:1.0.0"
+		manifests, err := render.GetManifestsFromApplicationSpec(context.TODO(), inputs, spec(reference, ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests.CombinedString()).To(ContainSubstring("name: app-config"))
+	})
+
+	Context("registry authentication", func() {
+
+		var (
+			dockerConfigDir string
+			originalConfig  string
+			hadConfig       bool
+		)
+
+		BeforeEach(func() {
+			var err error
+			dockerConfigDir, err = ioutil.TempDir("", "docker-config-")
+			Expect(err).NotTo(HaveOccurred())
+			originalConfig, hadConfig = os.LookupEnv("DOCKER_CONFIG")
+			Expect(os.Setenv("DOCKER_CONFIG", dockerConfigDir)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			if hadConfig {
+				os.Setenv("DOCKER_CONFIG", originalConfig)
+			} else {
+				os.Unsetenv("DOCKER_CONFIG")
+			}
+			os.RemoveAll(dockerConfigDir)
+		})
+
+		writeDockerConfig := func(config string) {
+			Expect(ioutil.WriteFile(filepath.Join(dockerConfigDir, "config.json"), []byte(config), 0600)).To(Succeed())
+		}
+
+		// A registry that issues a token challenge, and a token server that grants tokens to the given user, or to
+		// anyone if the user is empty.
+		tokenServer := func(user, password string) (*httptest.Server, *[]string) {
+			var scopes []string
+			var server *httptest.Server
+			registry := registryHandler(func(r *http.Request) bool {
+				return r.Header.Get("Authorization") == "Bearer token-for-charts/app"
+			})
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					if username, pass, _ := r.BasicAuth(); user != "" && (username != user || pass != password) {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					Expect(r.URL.Query().Get("service")).To(Equal("test-registry"))
+					scopes = append(scopes, r.URL.Query().Get("scope"))
+					w.Write([]byte(`{"token":"token-for-charts/app"}`))
+					return
+				}
+				if r.Header.Get("Authorization") != "Bearer token-for-charts/app" {
+					w.Header().Set("WWW-Authenticate",
+						`Bearer realm="`+server.URL+`/token",service="test-registry",scope="repository:charts/app:pull"`)
+				}
+				registry(w, r)
+			}))
+			return server, &scopes
+		}
+
+		fetch := func(server *httptest.Server) ([]byte, error) {
+			reference := strings.TrimPrefix(server.URL, "http://") + "/charts/app:1.0.0"
+			return render.FetchOciChartArchive(context.TODO(), &v1.OciChartLocation{Reference: reference})
+		}
+
+		It("answers token challenges anonymously without credentials", func() {
+			server, scopes := tokenServer("", "")
+			defer server.Close()
+
+			_, err := fetch(server)
+			Expect(err).NotTo(HaveOccurred())
+			// The token is reused for the blobs of the repository.
+			Expect(*scopes).To(Equal([]string{"repository:charts/app:pull"}))
+		})
+
+		It("answers token challenges with the credentials of the docker config", func() {
+			server, _ := tokenServer("user", "secret")
+			defer server.Close()
+
+			_, err := fetch(server)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to get a token"))
+
+			host := strings.TrimPrefix(server.URL, "http://")
+			writeDockerConfig(`{"auths":{"` + host + `":{"auth":"` + base64.StdEncoding.EncodeToString([]byte("user:secret")) + `"}}}`)
+			_, err = fetch(server)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("answers basic challenges with the credentials of the docker config", func() {
+			registry := registryHandler(func(r *http.Request) bool {
+				username, password, ok := r.BasicAuth()
+				return ok && username == "user" && password == "secret"
+			})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test-registry"`)
+				registry(w, r)
+			}))
+			defer server.Close()
+
+			_, err := fetch(server)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("requires credentials"))
+
+			host := strings.TrimPrefix(server.URL, "http://")
+			writeDockerConfig(`{"auths":{"http://` + host + `/v2/":{"username":"user","password":"secret"}}}`)
+			_, err = fetch(server)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reads the credentials of docker hub under its different names", func() {
+			writeDockerConfig(`{"auths":{"https://index.docker.io/v1/":{"auth":"` +
+				base64.StdEncoding.EncodeToString([]byte("user:secret")) + `"},"other.example.com":{}}}`)
+			credentials, err := render.GetDockerCredentials("docker.io")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(Equal(&render.RegistryCredentials{Username: "user", Password: "secret"}))
+
+			credentials, err = render.GetDockerCredentials("other.example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(BeNil())
+		})
+	})
+})
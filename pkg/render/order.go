@@ -0,0 +1,107 @@
+package render
+
+import (
+	"sort"
+
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The order in which kinds of resources are installed: namespaces and CRDs first, then RBAC, configuration and
+// workloads. Kinds that are not listed, i.e. custom resources, are installed after every listed kind except webhooks,
+// which come last so that they never reject resources before the workloads serving them are up.
+var InstallOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"PodSecurityPolicy",
+	"ServiceAccount",
+	"ClusterRole",
+	"ClusterRoleList",
+	"ClusterRoleBinding",
+	"ClusterRoleBindingList",
+	"Role",
+	"RoleList",
+	"RoleBinding",
+	"RoleBindingList",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"Service",
+	"PodDisruptionBudget",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"Ingress",
+	"APIService",
+}
+
+// Kinds installed after custom resources.
+var webhookKinds = []string{
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+var installPriorities = func() map[string]int {
+	priorities := make(map[string]int)
+	for i, kind := range InstallOrder {
+		priorities[kind] = i
+	}
+	for i, kind := range webhookKinds {
+		priorities[kind] = len(InstallOrder) + 1 + i
+	}
+	return priorities
+}()
+
+func installPriority(resource *unstructured.Unstructured) int {
+	if priority, ok := installPriorities[resource.GetKind()]; ok {
+		return priority
+	}
+	return len(InstallOrder)
+}
+
+// Sorts the resources in the order they should be installed. The resources of each of the spec's installation steps
+// are kept together, in the order of the steps. Within a step, resources are ordered by kind, then by namespace and
+// name, so the output is the same regardless of the order in which they were rendered.
+func SortResources(spec *hubv1.VersionedApplicationSpec, resources kuberesource.UnstructuredResources) kuberesource.UnstructuredResources {
+	stepIndexes := make(map[string]int)
+	for i, step := range spec.GetInstallationSteps().GetSteps() {
+		stepIndexes[step.GetName()] = i
+	}
+	stepIndex := func(resource *unstructured.Unstructured) int {
+		return stepIndexes[resource.GetLabels()[InstallationStepLabel]]
+	}
+
+	sorted := append(kuberesource.UnstructuredResources{}, resources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		res1, res2 := sorted[i], sorted[j]
+		if step1, step2 := stepIndex(res1), stepIndex(res2); step1 != step2 {
+			return step1 < step2
+		}
+		if priority1, priority2 := installPriority(res1), installPriority(res2); priority1 != priority2 {
+			return priority1 < priority2
+		}
+		if res1.GetKind() != res2.GetKind() {
+			return res1.GetKind() < res2.GetKind()
+		}
+		if res1.GetNamespace() != res2.GetNamespace() {
+			return res1.GetNamespace() < res2.GetNamespace()
+		}
+		if res1.GetName() != res2.GetName() {
+			return res1.GetName() < res2.GetName()
+		}
+		return res1.GetAPIVersion() < res2.GetAPIVersion()
+	})
+	return sorted
+}
@@ -0,0 +1,139 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/service-mesh-hub/pkg/render/validation"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
+)
+
+var _ = Describe("install order", func() {
+
+	names := func(resources kuberesource.UnstructuredResources) []string {
+		var names []string
+		for _, resource := range resources {
+			names = append(names, resource.GetKind()+"/"+resource.GetNamespace()+"/"+resource.GetName())
+		}
+		return names
+	}
+
+	It("sorts by kind, then namespace and name", func() {
+		resources, err := render.YamlToResources([]byte(`apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: webhook
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: b
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: b
+  namespace: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+  namespace: b
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: c
+  namespace: a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: a
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: role
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: a
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(render.SortResources(&v1.VersionedApplicationSpec{}, resources))).To(Equal([]string{
+			"Namespace//a",
+			"CustomResourceDefinition//widgets.example.com",
+			"ClusterRole//role",
+			"ConfigMap/a/config",
+			"Deployment/a/b",
+			"Deployment/a/c",
+			"Deployment/b/a",
+			"Widget/b/widget",
+			"ValidatingWebhookConfiguration//webhook",
+		}))
+	})
+
+	It("keeps the resources of installation steps in the order of the steps", func() {
+		step := func(name, yaml string) *v1.InstallationSteps_Step {
+			return &v1.InstallationSteps_Step{
+				Name: name,
+				Step: &v1.InstallationSteps_Step_InlineManifests{InlineManifests: &v1.InlineManifests{Yaml: yaml}},
+			}
+		}
+		spec := &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_InstallationSteps{
+				InstallationSteps: &v1.InstallationSteps{Steps: []*v1.InstallationSteps_Step{
+					step("crds", `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+`),
+					step("app", `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`),
+					step("namespace", `apiVersion: v1
+kind: Namespace
+metadata:
+  name: extra
+`),
+				}},
+			},
+		}
+		inputs := render.ValuesInputs{
+			Name:             "app",
+			InstallNamespace: "install",
+			Flavor:           &v1.Flavor{},
+			MeshRef:          core.ResourceRef{Name: "istio", Namespace: "istio-system"},
+		}
+		renderer := render.NewManifestRenderer(validation.NoopValidateResources, render.WithInstallOrder())
+		resources, err := renderer.ComputeResourcesForApplication(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(resources)).To(Equal([]string{
+			"CustomResourceDefinition//widgets.example.com",
+			"ConfigMap/install/config",
+			"Deployment/install/app",
+			"Namespace//extra",
+		}))
+	})
+})
@@ -30,15 +30,24 @@ var (
 func ConvertParamsToNestedMap(params map[string]string) (map[string]interface{}, error) {
 	nestedMap := make(map[string]interface{})
 	for k, v := range params {
-		merged := fmt.Sprintf("%s=%s", k, v)
-		err := strvals.ParseInto(merged, nestedMap)
-		if err != nil {
-			return nil, UnableToParseParameterError(err, k, v)
+		if err := parseParamInto(nestedMap, k, v, false); err != nil {
+			return nil, err
 		}
 	}
 	return nestedMap, nil
 }
 
+// Sets the param at its path in the values. The values of secret params are left out of the error.
+func parseParamInto(values map[string]interface{}, key, value string, secret bool) error {
+	if err := strvals.ParseInto(fmt.Sprintf("%s=%s", key, value), values); err != nil {
+		if secret {
+			value = RedactedValue
+		}
+		return UnableToParseParameterError(err, key, value)
+	}
+	return nil
+}
+
 func ConvertYamlStringToNestedMap(yamlString string) (map[string]interface{}, error) {
 	nestedMap := make(map[string]interface{})
 	err := yaml.Unmarshal([]byte(yamlString), &nestedMap)
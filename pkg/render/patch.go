@@ -0,0 +1,137 @@
+package render
+
+import (
+	"context"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Selector group that matches resources in the core ("") API group.
+const CoreGroup = "core"
+
+var (
+	MissingPatchError = errors.Errorf("resource patch must specify a json patch or a strategic merge patch")
+
+	FailedToApplyPatchError = func(err error, resource *unstructured.Unstructured) error {
+		return errors.Wrapf(err, "error patching %v %v.%v", resource.GetKind(), resource.GetNamespace(), resource.GetName())
+	}
+)
+
+// Applies the patches of the selected layer options, in the order in which the layers were selected.
+func ApplyLayerPatches(ctx context.Context, inputs ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	for _, layerInput := range inputs.Layers {
+		option, err := GetLayerOptionFromFlavor(layerInput.LayerId, layerInput.OptionId, inputs.Flavor)
+		if err != nil {
+			return nil, err
+		}
+		for _, patch := range option.GetPatches() {
+			if resources, err = ApplyPatch(ctx, patch, resources); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return resources, nil
+}
+
+// Applies the patch to each of the resources matched by its selector. Resources that are not selected are
+// returned unchanged.
+func ApplyPatch(ctx context.Context, patch *hubv1.ResourcePatch, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	var patched kuberesource.UnstructuredResources
+	matched := 0
+	for _, resource := range resources {
+		if !SelectorMatches(patch.GetSelector(), resource) {
+			patched = append(patched, resource)
+			continue
+		}
+		matched++
+		result, err := applyPatch(patch, resource)
+		if err != nil {
+			wrapped := FailedToApplyPatchError(err, resource)
+			contextutils.LoggerFrom(ctx).Errorw(wrapped.Error(), zap.Error(err), zap.Any("patch", patch))
+			return nil, wrapped
+		}
+		patched = append(patched, result)
+	}
+	if matched == 0 {
+		contextutils.LoggerFrom(ctx).Warnw("Patch did not match any resources", zap.Any("selector", patch.GetSelector()))
+	}
+	return patched, nil
+}
+
+func SelectorMatches(selector *hubv1.ResourceSelector, resource *unstructured.Unstructured) bool {
+	if selector == nil {
+		return true
+	}
+	group := resource.GroupVersionKind().Group
+	if selector.Group == CoreGroup {
+		if group != "" {
+			return false
+		}
+	} else if selector.Group != "" && selector.Group != group {
+		return false
+	}
+	if selector.Kind != "" && selector.Kind != resource.GetKind() {
+		return false
+	}
+	if selector.Name != "" && selector.Name != resource.GetName() {
+		return false
+	}
+	if selector.Namespace != "" && selector.Namespace != resource.GetNamespace() {
+		return false
+	}
+	return true
+}
+
+func applyPatch(patch *hubv1.ResourcePatch, resource *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	original, err := resource.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var patchedJson []byte
+	switch p := patch.GetPatch().(type) {
+	case *hubv1.ResourcePatch_JsonPatch:
+		patchJson, err := yaml.YAMLToJSON([]byte(p.JsonPatch))
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := jsonpatch.DecodePatch(patchJson)
+		if err != nil {
+			return nil, err
+		}
+		if patchedJson, err = decoded.Apply(original); err != nil {
+			return nil, err
+		}
+	case *hubv1.ResourcePatch_StrategicMergePatch:
+		patchJson, err := yaml.YAMLToJSON([]byte(p.StrategicMergePatch))
+		if err != nil {
+			return nil, err
+		}
+		// Strategic merge patches need the schema of the resource; fall back to JSON merge patches for unknown kinds.
+		if typed, err := scheme.Scheme.New(resource.GroupVersionKind()); err == nil {
+			patchedJson, err = strategicpatch.StrategicMergePatch(original, patchJson, typed)
+			if err != nil {
+				return nil, err
+			}
+		} else if patchedJson, err = jsonpatch.MergePatch(original, patchJson); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, MissingPatchError
+	}
+
+	result := &unstructured.Unstructured{}
+	if err := result.UnmarshalJSON(patchedJson); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
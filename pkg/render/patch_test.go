@@ -0,0 +1,128 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("patches", func() {
+
+	const manifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: productpage
+  namespace: default
+spec:
+  template:
+    spec:
+      containers:
+      - name: productpage
+        image: productpage:1.0
+      - name: sidecar
+        image: sidecar:1.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: productpage
+  namespace: default
+---
+apiVersion: example.io/v1
+kind: Widget
+metadata:
+  name: productpage
+  namespace: default
+spec:
+  size: small
+`
+
+	var resources kuberesource.UnstructuredResources
+
+	BeforeEach(func() {
+		var err error
+		resources, err = render.YamlToResources([]byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	find := func(resources kuberesource.UnstructuredResources, kind string) *unstructured.Unstructured {
+		for _, resource := range resources {
+			if resource.GetKind() == kind {
+				return resource
+			}
+		}
+		return nil
+	}
+
+	It("applies json patches to the selected resources", func() {
+		patch := &v1.ResourcePatch{
+			Selector: &v1.ResourceSelector{Name: "productpage"},
+			Patch: &v1.ResourcePatch_JsonPatch{JsonPatch: `
+- op: add
+  path: /metadata/labels
+  value:
+    patched: "true"
+`},
+		}
+		patched, err := render.ApplyPatch(context.TODO(), patch, resources)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patched).To(HaveLen(3))
+		for _, resource := range patched {
+			Expect(resource.GetLabels()).To(HaveKeyWithValue("patched", "true"))
+		}
+	})
+
+	It("merges containers by name with strategic merge patches", func() {
+		patch := &v1.ResourcePatch{
+			Selector: &v1.ResourceSelector{Group: "apps", Kind: "Deployment"},
+			Patch: &v1.ResourcePatch_StrategicMergePatch{StrategicMergePatch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: sidecar:2.0
+`},
+		}
+		patched, err := render.ApplyPatch(context.TODO(), patch, resources)
+		Expect(err).NotTo(HaveOccurred())
+		containers, _, _ := unstructured.NestedSlice(find(patched, "Deployment").Object, "spec", "template", "spec", "containers")
+		Expect(containers).To(HaveLen(2))
+		Expect(containers[0]).To(HaveKeyWithValue("image", "productpage:1.0"))
+		Expect(containers[1]).To(HaveKeyWithValue("image", "sidecar:2.0"))
+		Expect(find(patched, "Service")).To(Equal(find(resources, "Service")))
+	})
+
+	It("falls back to json merge patches for unknown kinds", func() {
+		patch := &v1.ResourcePatch{
+			Selector: &v1.ResourceSelector{Kind: "Widget"},
+			Patch:    &v1.ResourcePatch_StrategicMergePatch{StrategicMergePatch: "spec:\n  size: large\n"},
+		}
+		patched, err := render.ApplyPatch(context.TODO(), patch, resources)
+		Expect(err).NotTo(HaveOccurred())
+		size, _, _ := unstructured.NestedString(find(patched, "Widget").Object, "spec", "size")
+		Expect(size).To(Equal("large"))
+	})
+
+	It("selects core group resources", func() {
+		selector := &v1.ResourceSelector{Group: render.CoreGroup}
+		Expect(render.SelectorMatches(selector, find(resources, "Service"))).To(BeTrue())
+		Expect(render.SelectorMatches(selector, find(resources, "Deployment"))).To(BeFalse())
+	})
+
+	It("errors on invalid patches", func() {
+		patch := &v1.ResourcePatch{
+			Selector: &v1.ResourceSelector{Kind: "Service"},
+			Patch:    &v1.ResourcePatch_JsonPatch{JsonPatch: "- op: remove\n  path: /spec/missing\n"},
+		}
+		_, err := render.ApplyPatch(context.TODO(), patch, resources)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("error patching Service default.productpage"))
+	})
+})
@@ -0,0 +1,146 @@
+package render
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+)
+
+// Annotations describing how a resource was rendered.
+const (
+	ApplicationAnnotation = "service-mesh-hub.solo.io/application"
+	VersionAnnotation     = "service-mesh-hub.solo.io/version"
+	FlavorAnnotation      = "service-mesh-hub.solo.io/flavor"
+	LayersAnnotation      = "service-mesh-hub.solo.io/layers"
+	SourceAnnotation      = "service-mesh-hub.solo.io/source"
+	ValuesHashAnnotation  = "service-mesh-hub.solo.io/values_hash"
+)
+
+const inlineManifestsSource = "inline"
+
+// Annotates every resource with the application, version, flavor and layer options it was rendered from, the location
+// of its chart or manifests, and a hash of the effective helm values. Resources rendered by an installation step get
+// the source of that step.
+func ApplyProvenance(ctx context.Context, inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	values, err := ComputeValueOverrides(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(values))
+
+	var layers []string
+	for _, layer := range inputs.Layers {
+		layers = append(layers, layer.LayerId+"="+layer.OptionId)
+	}
+	provenance := map[string]string{
+		ApplicationAnnotation: inputs.Name,
+		VersionAnnotation:     spec.GetVersion(),
+		FlavorAnnotation:      inputs.Flavor.GetName(),
+		LayersAnnotation:      strings.Join(layers, ","),
+		SourceAnnotation:      GetSourceLocation(spec),
+		ValuesHashAnnotation:  "sha256:" + hex.EncodeToString(sum[:]),
+	}
+
+	stepSources := make(map[string]string)
+	for _, step := range spec.GetInstallationSteps().GetSteps() {
+		stepSources[step.GetName()] = getStepSourceLocation(step)
+	}
+	for _, resource := range resources {
+		annotations := mergeStringMaps(resource.GetAnnotations(), provenance)
+		if source, ok := stepSources[resource.GetLabels()[InstallationStepLabel]]; ok {
+			annotations[SourceAnnotation] = source
+		}
+		resource.SetAnnotations(annotations)
+	}
+	return resources, nil
+}
+
+// Describes the location of the chart or manifests of the spec. Specs with installation steps are described by the
+// location of each step, separated by commas.
+func GetSourceLocation(spec *hubv1.VersionedApplicationSpec) string {
+	switch installationSpec := spec.GetInstallationSpec().(type) {
+	case *hubv1.VersionedApplicationSpec_GithubChart:
+		return githubSourceLocation(installationSpec.GithubChart)
+	case *hubv1.VersionedApplicationSpec_HelmArchive:
+		return installationSpec.HelmArchive.GetUri()
+	case *hubv1.VersionedApplicationSpec_ManifestsArchive:
+		return installationSpec.ManifestsArchive.GetUri()
+	case *hubv1.VersionedApplicationSpec_LocalChart:
+		return installationSpec.LocalChart.GetPath()
+	case *hubv1.VersionedApplicationSpec_LocalManifests:
+		return installationSpec.LocalManifests.GetPath()
+	case *hubv1.VersionedApplicationSpec_HelmRepository:
+		return helmRepositorySourceLocation(installationSpec.HelmRepository)
+	case *hubv1.VersionedApplicationSpec_OciChart:
+		return ociSourceLocation(installationSpec.OciChart)
+	case *hubv1.VersionedApplicationSpec_GitChart:
+		return gitSourceLocation(installationSpec.GitChart)
+	case *hubv1.VersionedApplicationSpec_InlineManifests:
+		return inlineManifestsSource
+	case *hubv1.VersionedApplicationSpec_InstallationSteps:
+		var sources []string
+		for _, step := range installationSpec.InstallationSteps.GetSteps() {
+			sources = append(sources, getStepSourceLocation(step))
+		}
+		return strings.Join(sources, ",")
+	default:
+		return ""
+	}
+}
+
+func getStepSourceLocation(step *hubv1.InstallationSteps_Step) string {
+	switch installationSpec := step.GetStep().(type) {
+	case *hubv1.InstallationSteps_Step_GithubChart:
+		return githubSourceLocation(installationSpec.GithubChart)
+	case *hubv1.InstallationSteps_Step_HelmArchive:
+		return installationSpec.HelmArchive.GetUri()
+	case *hubv1.InstallationSteps_Step_ManifestsArchive:
+		return installationSpec.ManifestsArchive.GetUri()
+	case *hubv1.InstallationSteps_Step_LocalChart:
+		return installationSpec.LocalChart.GetPath()
+	case *hubv1.InstallationSteps_Step_LocalManifests:
+		return installationSpec.LocalManifests.GetPath()
+	case *hubv1.InstallationSteps_Step_HelmRepository:
+		return helmRepositorySourceLocation(installationSpec.HelmRepository)
+	case *hubv1.InstallationSteps_Step_OciChart:
+		return ociSourceLocation(installationSpec.OciChart)
+	case *hubv1.InstallationSteps_Step_GitChart:
+		return gitSourceLocation(installationSpec.GitChart)
+	case *hubv1.InstallationSteps_Step_InlineManifests:
+		return inlineManifestsSource
+	default:
+		return ""
+	}
+}
+
+func githubSourceLocation(location *hubv1.GithubRepositoryLocation) string {
+	return "github.com/" + location.GetOrg() + "/" + location.GetRepo() + "/" + location.GetDirectory() + "@" + location.GetRef()
+}
+
+func helmRepositorySourceLocation(location *hubv1.HelmRepositoryLocation) string {
+	return strings.TrimSuffix(location.GetRepositoryUrl(), "/") + "/" + location.GetChart() + "@" + location.GetVersion()
+}
+
+func ociSourceLocation(location *hubv1.OciChartLocation) string {
+	ref, err := ParseOciReference(location)
+	if err != nil {
+		return location.GetReference()
+	}
+	return ref.String()
+}
+
+// Like go-getter, a double slash separates the repository from the directory within it.
+func gitSourceLocation(location *hubv1.GitRepositoryLocation) string {
+	source := location.GetUrl()
+	if location.GetDirectory() != "" {
+		source += "//" + strings.Trim(location.GetDirectory(), "/")
+	}
+	if location.GetRef() != "" {
+		source += "@" + location.GetRef()
+	}
+	return source
+}
@@ -0,0 +1,128 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/service-mesh-hub/pkg/render/validation"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
+)
+
+var _ = Describe("provenance", func() {
+
+	const configMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  annotations:
+    existing: value
+`
+
+	flavor := &v1.Flavor{
+		Name: "default",
+		CustomizationLayers: []*v1.Layer{{
+			Id:      "size",
+			Options: []*v1.LayerOption{{Id: "small", HelmValues: "replicas: 1"}},
+		}},
+	}
+
+	inputsWith := func(params map[string]string) render.ValuesInputs {
+		return render.ValuesInputs{
+			Name:             "app",
+			InstallNamespace: "install",
+			Flavor:           flavor,
+			Layers:           []render.LayerInput{{LayerId: "size", OptionId: "small"}},
+			MeshRef:          core.ResourceRef{Name: "istio", Namespace: "istio-system"},
+			Params:           params,
+		}
+	}
+
+	annotationsByName := func(spec *v1.VersionedApplicationSpec, inputs render.ValuesInputs) map[string]map[string]string {
+		renderer := render.NewManifestRenderer(validation.NoopValidateResources, render.WithProvenance())
+		resources, err := renderer.ComputeResourcesForApplication(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+		annotations := make(map[string]map[string]string)
+		for _, resource := range resources {
+			annotations[resource.GetName()] = resource.GetAnnotations()
+		}
+		return annotations
+	}
+
+	It("annotates resources with the application, flavor, layers and source", func() {
+		spec := &v1.VersionedApplicationSpec{
+			Version: "1.0.0",
+			InstallationSpec: &v1.VersionedApplicationSpec_InlineManifests{
+				InlineManifests: &v1.InlineManifests{Yaml: configMap},
+			},
+		}
+		annotations := annotationsByName(spec, inputsWith(nil))["config"]
+		Expect(annotations).To(HaveKeyWithValue("existing", "value"))
+		Expect(annotations).To(HaveKeyWithValue(render.ApplicationAnnotation, "app"))
+		Expect(annotations).To(HaveKeyWithValue(render.VersionAnnotation, "1.0.0"))
+		Expect(annotations).To(HaveKeyWithValue(render.FlavorAnnotation, "default"))
+		Expect(annotations).To(HaveKeyWithValue(render.LayersAnnotation, "size=small"))
+		Expect(annotations).To(HaveKeyWithValue(render.SourceAnnotation, "inline"))
+		Expect(annotations[render.ValuesHashAnnotation]).To(HavePrefix("sha256:"))
+	})
+
+	It("hashes the effective values", func() {
+		spec := &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_InlineManifests{
+				InlineManifests: &v1.InlineManifests{Yaml: configMap},
+			},
+			Parameters: []*v1.Parameter{{Name: "replicas"}},
+		}
+		hash := func(params map[string]string) string {
+			return annotationsByName(spec, inputsWith(params))["config"][render.ValuesHashAnnotation]
+		}
+		Expect(hash(map[string]string{"replicas": "2"})).To(Equal(hash(map[string]string{"replicas": "2"})))
+		Expect(hash(map[string]string{"replicas": "2"})).NotTo(Equal(hash(map[string]string{"replicas": "3"})))
+		Expect(hash(map[string]string{"replicas": "2"})).NotTo(Equal(hash(nil)))
+	})
+
+	It("uses the source of the step that rendered each resource", func() {
+		spec := &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_InstallationSteps{
+				InstallationSteps: &v1.InstallationSteps{Steps: []*v1.InstallationSteps_Step{
+					{
+						Name: "config",
+						Step: &v1.InstallationSteps_Step_InlineManifests{
+							InlineManifests: &v1.InlineManifests{Yaml: configMap},
+						},
+					},
+					{
+						Name: "chart",
+						Step: &v1.InstallationSteps_Step_GitChart{
+							GitChart: &v1.GitRepositoryLocation{Url: "https://git.example.com/charts.git", Ref: "v1", Directory: "app"},
+						},
+					},
+				}},
+			},
+		}
+		Expect(render.GetSourceLocation(spec)).To(Equal("inline,https://git.example.com/charts.git//app@v1"))
+
+		spec.GetInstallationSteps().Steps = spec.GetInstallationSteps().Steps[:1]
+		Expect(annotationsByName(spec, inputsWith(nil))["config"]).To(HaveKeyWithValue(render.SourceAnnotation, "inline"))
+	})
+
+	It("describes the location of remote sources", func() {
+		Expect(render.GetSourceLocation(&v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_HelmRepository{
+				HelmRepository: &v1.HelmRepositoryLocation{RepositoryUrl: "https://charts.example.com/", Chart: "app", Version: "1.2.3"},
+			},
+		})).To(Equal("https://charts.example.com/app@1.2.3"))
+		Expect(render.GetSourceLocation(&v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_OciChart{
+				OciChart: &v1.OciChartLocation{Reference: "registry.example.com/charts/app:1.2.3"},
+			},
+		})).To(Equal("registry.example.com/charts/app:1.2.3"))
+		Expect(render.GetSourceLocation(&v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_GithubChart{
+				GithubChart: &v1.GithubRepositoryLocation{Org: "solo-io", Repo: "charts", Ref: "master", Directory: "app"},
+			},
+		})).To(Equal("github.com/solo-io/charts/app@master"))
+	})
+})
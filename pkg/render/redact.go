@@ -0,0 +1,270 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/helm/helm/pkg/strvals"
+	errors "github.com/rotisserie/eris"
+	"github.com/solo-io/go-utils/contextutils"
+	hubv1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Replaces the values of SECRET params in logs and errors.
+const RedactedValue = "<redacted>"
+
+// Secrets shorter than this are only masked where they make up a whole value, since masking them wherever they occur in
+// logs and errors would mangle unrelated text.
+const minRedactedSecretLength = 4
+
+// Masks the values of SECRET params, and the values at the paths they are set on, so that they are not logged or
+// returned in errors.
+type redactor struct {
+	paths   []string
+	secrets []string
+	// Secrets that are too short to be masked within other text.
+	shortSecrets map[string]bool
+}
+
+func newRedactor(inputs ValuesInputs) *redactor {
+	r := &redactor{shortSecrets: make(map[string]bool)}
+	r.add(inputs)
+	return r
+}
+
+// Adds the values of the secret params of the inputs, i.e. once they are rendered.
+func (r *redactor) add(inputs ValuesInputs) {
+	for _, name := range inputs.SecretParams {
+		value := inputs.Params[name]
+		if value == "" || IsParamTemplate(value) {
+			continue
+		}
+		r.paths = appendUnique(r.paths, name)
+		if len(value) < minRedactedSecretLength {
+			r.shortSecrets[value] = true
+			continue
+		}
+		r.secrets = appendUnique(r.secrets, value)
+		// Also mask the secret where it is formatted with %q, e.g. in errors about it.
+		if quoted := strconv.Quote(value); quoted[1:len(quoted)-1] != value {
+			r.secrets = appendUnique(r.secrets, quoted[1:len(quoted)-1])
+		}
+	}
+	// Replace longer secrets first, so that secrets containing others are masked entirely.
+	sort.SliceStable(r.secrets, func(i, j int) bool {
+		return len(r.secrets[i]) > len(r.secrets[j])
+	})
+}
+
+func isSecretParam(inputs ValuesInputs, name string) bool {
+	for _, secretParam := range inputs.SecretParams {
+		if secretParam == name {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func (r *redactor) String(s string) string {
+	if r.shortSecrets[s] {
+		return RedactedValue
+	}
+	for _, secret := range r.secrets {
+		s = strings.Replace(s, secret, RedactedValue, -1)
+	}
+	return s
+}
+
+// Masks the message of the error and of every error it wraps.
+func (r *redactor) Error(err error) error {
+	redacted, _ := r.redactError(err)
+	return redacted
+}
+
+// Returns whether a secret was masked in the chain, so that errors without secrets are returned as is.
+func (r *redactor) redactError(err error) (error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	cause, causeRedacted := r.redactError(errors.Unwrap(err))
+	message := r.String(err.Error())
+	if message == err.Error() && !causeRedacted {
+		return err, false
+	}
+	return &redactedError{message: message, original: err, cause: cause}, true
+}
+
+// Masks the values YAML, replacing the values at the paths of the secret params as well as the secrets found in
+// other values, e.g. in templates that reference secret params.
+func (r *redactor) Values(values string) string {
+	if len(r.paths) == 0 {
+		return values
+	}
+	valuesMap, err := ConvertYamlStringToNestedMap(values)
+	if err != nil {
+		return r.String(values)
+	}
+	redacted, err := ConvertNestedMapToYaml(r.ValuesMap(valuesMap))
+	if err != nil {
+		return r.String(values)
+	}
+	return redacted
+}
+
+// Returns a copy of the values map, masked like Values.
+func (r *redactor) ValuesMap(valuesMap map[string]interface{}) map[string]interface{} {
+	if len(r.paths) == 0 {
+		return valuesMap
+	}
+	redacted, _ := r.value(valuesMap).(map[string]interface{})
+	for _, path := range r.paths {
+		// Paths are set the same way as the params, see ConvertParamsToNestedMap.
+		_ = strvals.ParseInto(fmt.Sprintf("%s=%s", path, RedactedValue), redacted)
+	}
+	return redacted
+}
+
+// Returns a copy of the value with the secrets masked in every string it contains.
+func (r *redactor) value(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		return r.String(t)
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(t))
+		for key, value := range t {
+			redacted[key] = r.value(value)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(t))
+		for i, value := range t {
+			redacted[i] = r.value(value)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+// An error whose message is masked. It unwraps to its masked cause rather than to the original error, so that the
+// secrets cannot be read from the chain, and matches the errors the original matches with errors.Is.
+type redactedError struct {
+	message  string
+	original error
+	cause    error
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.cause
+}
+
+// Only the original error itself is compared, the errors it wraps are matched through the cause.
+func (e *redactedError) Is(target error) bool {
+	if is, ok := e.original.(interface{ Is(error) bool }); ok && is.Is(target) {
+		return true
+	}
+	return target != nil && reflect.TypeOf(target).Comparable() && e.original == target
+}
+
+// Returns a context whose logger masks the secrets in every message and field it logs.
+func withRedactedLogger(ctx context.Context, r *redactor) context.Context {
+	logger := contextutils.LoggerFrom(ctx).Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &redactingCore{Core: core, redactor: r}
+	}))
+	return contextutils.WithExistingLogger(ctx, logger.Sugar())
+}
+
+type redactingCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.fields(fields)), redactor: c.redactor}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redactor.String(entry.Message)
+	return c.Core.Write(entry, c.fields(fields))
+}
+
+func (c *redactingCore) fields(fields []zapcore.Field) []zapcore.Field {
+	if len(c.redactor.secrets) == 0 && len(c.redactor.shortSecrets) == 0 {
+		return fields
+	}
+	redacted := make([]zapcore.Field, 0, len(fields))
+	for _, field := range fields {
+		switch field.Type {
+		case zapcore.StringType:
+			field.String = c.redactor.String(field.String)
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok {
+				field = zap.NamedError(field.Key, c.redactor.Error(err))
+			}
+		case zapcore.StringerType:
+			if stringer, ok := field.Interface.(fmt.Stringer); ok {
+				field = zap.String(field.Key, c.redactor.String(stringer.String()))
+			}
+		case zapcore.ReflectType, zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+			field = c.reflectField(field)
+		}
+		redacted = append(redacted, field)
+	}
+	return redacted
+}
+
+// Masks arbitrary values through their JSON representation, which is how they are logged.
+func (c *redactingCore) reflectField(field zapcore.Field) zapcore.Field {
+	data, err := json.Marshal(field.Interface)
+	if err != nil {
+		return field
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return field
+	}
+	if redacted := c.redactor.value(value); !reflect.DeepEqual(value, redacted) {
+		return zap.Reflect(field.Key, redacted)
+	}
+	return field
+}
+
+// Adds the SECRET params declared by the spec, the flavor and the selected layer options to the secret params of the
+// inputs.
+func withSecretParams(inputs ValuesInputs, spec *hubv1.VersionedApplicationSpec) ValuesInputs {
+	secretParams := append([]string{}, inputs.SecretParams...)
+	for name, param := range getDeclaredParams(spec, inputs.Flavor, getSelectedOptions(inputs)) {
+		if param.GetType() == hubv1.ParameterType_SECRET {
+			secretParams = appendUnique(secretParams, name)
+		}
+	}
+	inputs.SecretParams = secretParams
+	return inputs
+}
@@ -0,0 +1,189 @@
+package render_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gogo/protobuf/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/go-utils/contextutils"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/service-mesh-hub/pkg/render/validation"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var _ = Describe("secret redaction", func() {
+
+	const secret = "hunter2\nsecond-line"
+
+	var (
+		dir  string
+		ctx  context.Context
+		logs *observer.ObservedLogs
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "redact-")
+		Expect(err).NotTo(HaveOccurred())
+
+		var core zapcore.Core
+		core, logs = observer.New(zapcore.DebugLevel)
+		ctx = contextutils.WithExistingLogger(context.TODO(), zap.New(core).Sugar())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(name, content string) {
+		filename := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(filename), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filename, []byte(content), 0644)).To(Succeed())
+	}
+
+	// Returns every message and field that was logged.
+	loggedText := func() string {
+		var text string
+		for _, entry := range logs.All() {
+			text += entry.Message + "\n"
+			for key, value := range entry.ContextMap() {
+				text += key + ": " + fmt.Sprint(value) + "\n"
+			}
+		}
+		return text
+	}
+
+	spec := func(parameters ...*v1.Parameter) *v1.VersionedApplicationSpec {
+		return &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_LocalChart{
+				LocalChart: &v1.LocalLocation{Path: filepath.Join(dir, "chart")},
+			},
+			Parameters: parameters,
+		}
+	}
+
+	inputs := func(params map[string]string) render.ValuesInputs {
+		return render.ValuesInputs{
+			Name:             "app",
+			InstallNamespace: "install",
+			Flavor:           &v1.Flavor{},
+			Params:           params,
+		}
+	}
+
+	It("redacts secret params from the values logged and the errors returned when rendering fails", func() {
+		writeFile("chart/Chart.yaml", "apiVersion: v1\nname: app\nversion: 0.1.0\n")
+		writeFile("chart/templates/configmap.yaml", `{{ fail (printf "cannot use %v and %v" .Values.tls.key .Values.url) }}`)
+
+		renderer := render.NewManifestRenderer(validation.NoopValidateResources)
+		_, err := renderer.ComputeResourcesForApplication(ctx, inputs(map[string]string{
+			"tls.key": secret,
+			"url":     "https://admin:{{ index .Params \"tls.key\" }}@example.com",
+		}), spec(
+			&v1.Parameter{Name: "tls.key", Type: v1.ParameterType_SECRET},
+			&v1.Parameter{Name: "url"},
+		))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cannot use " + render.RedactedValue))
+		Expect(err.Error()).NotTo(ContainSubstring("hunter2"))
+
+		logged := loggedText()
+		Expect(logged).To(ContainSubstring("key: " + render.RedactedValue))
+		Expect(logged).To(ContainSubstring("https://admin:" + render.RedactedValue + "@example.com"))
+		Expect(logged).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("redacts secret params from validation errors", func() {
+		renderer := render.NewManifestRenderer(validation.NoopValidateResources)
+		_, err := renderer.ComputeResourcesForApplication(ctx, inputs(map[string]string{"password": "hunter2"}), spec(
+			&v1.Parameter{Name: "password", Type: v1.ParameterType_SECRET, Pattern: "^[0-9]+$"},
+		))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(render.RedactedValue))
+		Expect(err.Error()).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("redacts the secret params named by the inputs when computing values", func() {
+		in := inputs(map[string]string{"password": "hunter2"})
+		in.SpecDefinedValues = "password:\n  nested: table\n"
+		in.SecretParams = []string{"password"}
+		_, err := render.ComputeValueOverrides(ctx, in)
+		Expect(err).NotTo(HaveOccurred())
+
+		logged := loggedText()
+		Expect(logged).To(ContainSubstring("coalescing table into value"))
+		Expect(logged).To(ContainSubstring(render.RedactedValue))
+		Expect(logged).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("redacts every error of the chain and still matches the original errors", func() {
+		writeFile("chart/Chart.yaml", "apiVersion: v1\nname: app\nversion: 0.1.0\n")
+		writeFile("chart/templates/configmap.yaml", `{{ fail .Values.password }}`)
+
+		renderer := render.NewManifestRenderer(validation.NoopValidateResources)
+		_, err := renderer.ComputeResourcesForApplication(ctx, inputs(map[string]string{"password": "hunter2"}), spec(
+			&v1.Parameter{Name: "password", Type: v1.ParameterType_SECRET},
+		))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Unwrap(err)).To(HaveOccurred())
+		for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+			Expect(cause.Error()).NotTo(ContainSubstring("hunter2"))
+			Expect(fmt.Sprintf("%+v", cause)).NotTo(ContainSubstring("hunter2"))
+		}
+		Expect(errors.Is(err, render.FailedToRenderManifestsError(errors.New("")))).To(BeTrue())
+
+		in := inputs(map[string]string{"password": "hunter2"})
+		in.Layers = []render.LayerInput{{LayerId: "missing"}}
+		in.Flavor = &v1.Flavor{CustomizationLayers: []*v1.Layer{{Id: "a"}, {Id: "b"}}}
+		_, err = renderer.ComputeResourcesForApplication(ctx, in, spec(
+			&v1.Parameter{Name: "password", Type: v1.ParameterType_SECRET},
+		))
+		Expect(errors.Is(err, render.IncorrectNumberOfInputLayersError)).To(BeTrue())
+	})
+
+	It("leaves secret values out of the errors about them", func() {
+		in := inputs(map[string]string{"password": "{hunter2"})
+		in.SecretParams = []string{"password"}
+		_, err := render.ComputeValueOverrides(ctx, in)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(render.UnableToParseParameterError(errors.New(""), "password", render.RedactedValue).Error()))
+		Expect(errors.Unwrap(err)).NotTo(BeNil())
+		Expect(errors.Unwrap(err).Error()).NotTo(ContainSubstring("hunter2"))
+
+		param := &v1.Parameter{Name: "password", Type: v1.ParameterType_SECRET, MinLength: &types.UInt32Value{Value: 10}}
+		err = render.ValidateParamValue(param, "hunter2")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(render.RedactedValue))
+		Expect(err.Error()).NotTo(ContainSubstring("hunter2"))
+	})
+
+	It("masks short secrets only where they make up a whole value", func() {
+		in := inputs(map[string]string{"pin": "a"})
+		in.SecretParams = []string{"pin"}
+		in.SpecDefinedValues = "pin:\n  nested: table\n"
+		_, err := render.ComputeValueOverrides(ctx, in)
+		Expect(err).NotTo(HaveOccurred())
+
+		logged := loggedText()
+		Expect(logged).To(ContainSubstring("coalescing table into value"))
+		Expect(logged).To(ContainSubstring("key: pin"))
+		Expect(logged).To(ContainSubstring("value: " + render.RedactedValue))
+		Expect(logged).NotTo(ContainSubstring("value: a\n"))
+	})
+
+	It("leaves logs and errors alone without secret params", func() {
+		_, err := render.ComputeValueOverrides(ctx, render.ValuesInputs{Params: map[string]string{"a": "b"}, UserDefinedValues: "a: [b"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).NotTo(ContainSubstring(render.RedactedValue))
+		Expect(loggedText()).To(ContainSubstring("a: [b"))
+	})
+})
@@ -17,23 +17,82 @@ type ManifestRenderer interface {
 
 type manifestRenderer struct {
 	validateEnvironment validation.ValidateResourceDependencies
+	fetcher             ArtifactFetcher
+	transformers        []Transformer
+	provenance          bool
+	installOrder        bool
 }
 
-func NewManifestRenderer(validateFn validation.ValidateResourceDependencies) ManifestRenderer {
-	return &manifestRenderer{validateEnvironment: validateFn}
+// Customizes the behavior of a ManifestRenderer.
+type Option func(*manifestRenderer)
+
+// Retrieve the charts and archives referenced by installation specs with the given fetcher.
+// By default, they are downloaded from their remote locations on every render.
+func WithArtifactFetcher(fetcher ArtifactFetcher) Option {
+	return func(m *manifestRenderer) {
+		m.fetcher = fetcher
+	}
+}
+
+// Run the transformers, in order, on the resources of every rendered application.
+func WithTransformers(transformers ...Transformer) Option {
+	return func(m *manifestRenderer) {
+		m.transformers = append(m.transformers, transformers...)
+	}
 }
 
+// Annotate every rendered resource with the application, version, flavor, layer options, source and values it was
+// rendered from. See ApplyProvenance.
+func WithProvenance() Option {
+	return func(m *manifestRenderer) {
+		m.provenance = true
+	}
+}
+
+// Sort the rendered resources in the order they should be installed. See SortResources.
+func WithInstallOrder() Option {
+	return func(m *manifestRenderer) {
+		m.installOrder = true
+	}
+}
+
+func NewManifestRenderer(validateFn validation.ValidateResourceDependencies, opts ...Option) ManifestRenderer {
+	renderer := &manifestRenderer{
+		validateEnvironment: validateFn,
+		fetcher:             NewRemoteArtifactFetcher(),
+	}
+	for _, opt := range opts {
+		opt(renderer)
+	}
+	return renderer
+}
+
+// The values of secret params are redacted from the logs and from the returned error.
 func (m *manifestRenderer) ComputeResourcesForApplication(ctx context.Context, inputs ValuesInputs, spec *v1.VersionedApplicationSpec) (kuberesource.UnstructuredResources, error) {
+	inputs = withSecretParams(inputs, spec)
+	redactor := newRedactor(inputs)
+	resources, err := m.computeResources(withRedactedLogger(ctx, redactor), redactor, inputs, spec)
+	if err != nil {
+		return nil, redactor.Error(err)
+	}
+	return resources, nil
+}
+
+func (m *manifestRenderer) computeResources(ctx context.Context, redactor *redactor, inputs ValuesInputs, spec *v1.VersionedApplicationSpec) (kuberesource.UnstructuredResources, error) {
 	if err := ValidateInputs(inputs, *spec, m.validateEnvironment); err != nil {
 		return nil, err
 	}
 
 	inputs, err := ExecInputValuesTemplates(inputs)
 	if err != nil {
-		return nil, FailedRenderValueTemplatesError(err)
+		return nil, err
+	}
+	redactor.add(inputs)
+	if err := ValidateParamTypes(inputs, spec); err != nil {
+		return nil, err
 	}
 
-	manifests, err := GetManifestsFromApplicationSpec(ctx, inputs, spec)
+	manifests, err := getManifestsFromApplicationSpec(ctx, m.fetcher, inputs, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -42,5 +101,26 @@ func (m *manifestRenderer) ComputeResourcesForApplication(ctx context.Context, i
 	if err != nil {
 		return nil, err
 	}
-	return FilterByLabel(ctx, spec, rawResources), nil
+	resources := ApplyInstallNamespace(ctx, inputs, spec, FilterByLabel(ctx, spec, rawResources))
+	resources, err = ApplyLayerPatches(ctx, inputs, resources)
+	if err != nil {
+		return nil, err
+	}
+	resources, err = ApplyKustomizeLayers(ctx, m.fetcher, inputs, resources)
+	if err != nil {
+		return nil, err
+	}
+	if m.provenance {
+		if resources, err = ApplyProvenance(ctx, inputs, spec, resources); err != nil {
+			return nil, err
+		}
+	}
+	resources, err = ApplyTransformers(ctx, m.transformers, inputs, resources)
+	if err != nil {
+		return nil, err
+	}
+	if m.installOrder {
+		resources = SortResources(spec, resources)
+	}
+	return resources, nil
 }
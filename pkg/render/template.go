@@ -0,0 +1,241 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+	errors "github.com/rotisserie/eris"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	ParamReferenceCycleError = func(cycle []string) error {
+		return errors.Errorf("parameters reference each other in a cycle: %v", strings.Join(cycle, " -> "))
+	}
+)
+
+// Sprig functions that are left out of templates, like helm does, so that rendering does not depend on the environment.
+var excludedSprigFuncs = []string{"env", "expandenv"}
+
+// Returns the functions available to the templates in values, parameters and inline manifests. These are the ones chart
+// authors know from helm: the sprig library, along with toYaml, fromYaml, toJson, fromJson and required.
+func TemplateFuncs() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	for _, name := range excludedSprigFuncs {
+		delete(funcs, name)
+	}
+	funcs["toYaml"] = toYaml
+	funcs["fromYaml"] = fromYaml
+	funcs["toJson"] = toJson
+	funcs["fromJson"] = fromJson
+	funcs["required"] = required
+	return funcs
+}
+
+// Parses and executes the template, returning errors rather than panicking on malformed templates.
+func execTemplate(name, text string, data interface{}) (string, error) {
+	tpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	if err := tpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func toYaml(v interface{}) string {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		// Like helm, swallow errors so that templates can be used inside other functions.
+		return ""
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+func fromYaml(str string) map[string]interface{} {
+	m := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(str), &m); err != nil {
+		m["Error"] = err.Error()
+	}
+	return m
+}
+
+func toJson(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func fromJson(str string) map[string]interface{} {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(str), &m); err != nil {
+		m["Error"] = err.Error()
+	}
+	return m
+}
+
+func required(message string, v interface{}) (interface{}, error) {
+	if v == nil {
+		return v, errors.New(message)
+	}
+	if s, ok := v.(string); ok && s == "" {
+		return v, errors.New(message)
+	}
+	return v, nil
+}
+
+// Orders the parameters so that every parameter comes after the parameters its template references through .Params.
+// Independent parameters are ordered by name.
+func orderParams(params map[string]string) ([]string, error) {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	references := make(map[string][]string, len(params))
+	for _, name := range names {
+		refs, err := paramReferences(name, params[name])
+		if err != nil {
+			return nil, FailedRenderValueTemplatesError(err, "parameter "+name)
+		}
+		for _, ref := range refs {
+			if _, ok := params[ref]; ok {
+				references[name] = append(references[name], ref)
+			}
+		}
+		sort.Strings(references[name])
+	}
+
+	var ordered []string
+	visited := make(map[string]bool)
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		if visited[name] {
+			return nil
+		}
+		for i, visiting := range path {
+			if visiting == name {
+				return ParamReferenceCycleError(append(append([]string{}, path[i:]...), name))
+			}
+		}
+		path = append(path, name)
+		for _, ref := range references[name] {
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		visited[name] = true
+		ordered = append(ordered, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Returns the names of the parameters referenced by the template, as .Params.name, $.Params.name or
+// index .Params "name".
+func paramReferences(name, text string) ([]string, error) {
+	tpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, t := range tpl.Templates() {
+		if t.Tree != nil {
+			refs = append(refs, nodeParamReferences(t.Tree.Root)...)
+		}
+	}
+	return refs, nil
+}
+
+func nodeParamReferences(node parse.Node) []string {
+	var refs []string
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			refs = append(refs, nodeParamReferences(child)...)
+		}
+	case *parse.ActionNode:
+		refs = nodeParamReferences(n.Pipe)
+	case *parse.IfNode:
+		refs = branchParamReferences(&n.BranchNode)
+	case *parse.RangeNode:
+		refs = branchParamReferences(&n.BranchNode)
+	case *parse.WithNode:
+		refs = branchParamReferences(&n.BranchNode)
+	case *parse.TemplateNode:
+		refs = nodeParamReferences(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			refs = append(refs, nodeParamReferences(cmd)...)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) == 3 && isIdentifier(n.Args[0], "index") && isParamsMap(n.Args[1]) {
+			if key, ok := n.Args[2].(*parse.StringNode); ok {
+				refs = append(refs, key.Text)
+			}
+		}
+		for _, arg := range n.Args {
+			refs = append(refs, nodeParamReferences(arg)...)
+		}
+	case *parse.ChainNode:
+		refs = nodeParamReferences(n.Node)
+	case *parse.FieldNode:
+		refs = identParamReferences(n.Ident)
+	case *parse.VariableNode:
+		if len(n.Ident) > 0 && n.Ident[0] == "$" {
+			refs = identParamReferences(n.Ident[1:])
+		}
+	}
+	return refs
+}
+
+func branchParamReferences(n *parse.BranchNode) []string {
+	refs := nodeParamReferences(n.Pipe)
+	refs = append(refs, nodeParamReferences(n.List)...)
+	return append(refs, nodeParamReferences(n.ElseList)...)
+}
+
+func identParamReferences(ident []string) []string {
+	if len(ident) >= 2 && ident[0] == "Params" {
+		return []string{ident[1]}
+	}
+	return nil
+}
+
+func isIdentifier(node parse.Node, name string) bool {
+	identifier, ok := node.(*parse.IdentifierNode)
+	return ok && identifier.Ident == name
+}
+
+func isParamsMap(node parse.Node) bool {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		return len(n.Ident) == 1 && n.Ident[0] == "Params"
+	case *parse.VariableNode:
+		return len(n.Ident) == 2 && n.Ident[0] == "$" && n.Ident[1] == "Params"
+	}
+	return false
+}
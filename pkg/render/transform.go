@@ -0,0 +1,198 @@
+package render
+
+import (
+	"context"
+	"strings"
+
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+)
+
+// Mutates the resources of an application after they have been rendered and customized by the spec's layers.
+type Transformer interface {
+	Transform(ctx context.Context, inputs ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error)
+}
+
+// Adapts a function to the Transformer interface.
+type TransformerFunc func(ctx context.Context, inputs ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error)
+
+func (f TransformerFunc) Transform(ctx context.Context, inputs ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	return f(ctx, inputs, resources)
+}
+
+// Runs the transformers in order, feeding the output of each one to the next.
+func ApplyTransformers(ctx context.Context, transformers []Transformer, inputs ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+	for _, transformer := range transformers {
+		var err error
+		if resources, err = transformer.Transform(ctx, inputs, resources); err != nil {
+			return nil, err
+		}
+	}
+	return resources, nil
+}
+
+// Adds the labels to the metadata of every resource. Selectors and pod templates are left untouched, since changing
+// them would break upgrades of existing workloads.
+func CommonLabels(labels map[string]string) Transformer {
+	return TransformerFunc(func(_ context.Context, _ ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+		for _, resource := range resources {
+			resource.SetLabels(mergeStringMaps(resource.GetLabels(), labels))
+		}
+		return resources, nil
+	})
+}
+
+// Adds the annotations to the metadata of every resource.
+func CommonAnnotations(annotations map[string]string) Transformer {
+	return TransformerFunc(func(_ context.Context, _ ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+		for _, resource := range resources {
+			resource.SetAnnotations(mergeStringMaps(resource.GetAnnotations(), annotations))
+		}
+		return resources, nil
+	})
+}
+
+// Kinds that are not prefixed: namespaces, which the other resources are placed in by name, and kinds whose names are
+// dictated by their content.
+var unprefixableKinds = map[string]bool{
+	"Namespace":                true,
+	"CustomResourceDefinition": true,
+	"APIService":               true,
+}
+
+// A field that holds the name of a resource of the given kind. An empty kind means that the kind is held by the
+// "kind" field next to the name, like in role refs. Fields of lists are suffixed with "[]".
+type nameReference struct {
+	kind string
+	path string
+}
+
+// The fields of pod specs that reference other resources by name.
+var podSpecNameReferences = func() []nameReference {
+	references := []nameReference{
+		{kind: "ServiceAccount", path: "serviceAccountName"},
+		{kind: "ServiceAccount", path: "serviceAccount"},
+		{kind: "PriorityClass", path: "priorityClassName"},
+		{kind: "Secret", path: "imagePullSecrets[].name"},
+		{kind: "ConfigMap", path: "volumes[].configMap.name"},
+		{kind: "Secret", path: "volumes[].secret.secretName"},
+		{kind: "PersistentVolumeClaim", path: "volumes[].persistentVolumeClaim.claimName"},
+		{kind: "ConfigMap", path: "volumes[].projected.sources[].configMap.name"},
+		{kind: "Secret", path: "volumes[].projected.sources[].secret.name"},
+	}
+	for _, containers := range []string{"containers[]", "initContainers[]"} {
+		references = append(references,
+			nameReference{kind: "ConfigMap", path: containers + ".envFrom[].configMapRef.name"},
+			nameReference{kind: "Secret", path: containers + ".envFrom[].secretRef.name"},
+			nameReference{kind: "ConfigMap", path: containers + ".env[].valueFrom.configMapKeyRef.name"},
+			nameReference{kind: "Secret", path: containers + ".env[].valueFrom.secretKeyRef.name"},
+		)
+	}
+	return references
+}()
+
+// The paths of the pod specs of workloads, by kind.
+var podSpecPaths = map[string]string{
+	"Pod":                   "spec",
+	"Deployment":            "spec.template.spec",
+	"DaemonSet":             "spec.template.spec",
+	"StatefulSet":           "spec.template.spec",
+	"ReplicaSet":            "spec.template.spec",
+	"ReplicationController": "spec.template.spec",
+	"Job":                   "spec.template.spec",
+	"CronJob":               "spec.jobTemplate.spec.template.spec",
+}
+
+// The fields of other kinds that reference resources by name, by kind.
+var nameReferences = map[string][]nameReference{
+	"RoleBinding":        {{path: "roleRef.name"}, {path: "subjects[].name"}},
+	"ClusterRoleBinding": {{path: "roleRef.name"}, {path: "subjects[].name"}},
+	"ServiceAccount": {
+		{kind: "Secret", path: "secrets[].name"},
+		{kind: "Secret", path: "imagePullSecrets[].name"},
+	},
+	"StatefulSet":             {{kind: "Service", path: "spec.serviceName"}},
+	"HorizontalPodAutoscaler": {{path: "spec.scaleTargetRef.name"}},
+	"PersistentVolumeClaim": {
+		{kind: "PersistentVolume", path: "spec.volumeName"},
+		{kind: "StorageClass", path: "spec.storageClassName"},
+	},
+	"Ingress": {
+		{kind: "Service", path: "spec.backend.serviceName"},
+		{kind: "Service", path: "spec.rules[].http.paths[].backend.serviceName"},
+		{kind: "Service", path: "spec.defaultBackend.service.name"},
+		{kind: "Service", path: "spec.rules[].http.paths[].backend.service.name"},
+		{kind: "Secret", path: "spec.tls[].secretName"},
+	},
+	"MutatingWebhookConfiguration":   {{kind: "Service", path: "webhooks[].clientConfig.service.name"}},
+	"ValidatingWebhookConfiguration": {{kind: "Service", path: "webhooks[].clientConfig.service.name"}},
+	"APIService":                     {{kind: "Service", path: "spec.service.name"}},
+	"CustomResourceDefinition": {
+		{kind: "Service", path: "spec.conversion.webhookClientConfig.service.name"},
+		{kind: "Service", path: "spec.conversion.webhook.clientConfig.service.name"},
+	},
+}
+
+// Prefixes the name of every resource, except for namespaces and the resources whose name is dictated by their
+// content, i.e. CRDs. Like kustomize's namePrefix, the references to renamed resources are renamed along with them,
+// e.g. the service account and config maps of a deployment, or the service of a webhook. Labels and selectors are
+// left untouched.
+func NamePrefix(prefix string) Transformer {
+	return TransformerFunc(func(_ context.Context, _ ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+		renamed := make(map[string]bool)
+		for _, resource := range resources {
+			if unprefixableKinds[resource.GetKind()] {
+				continue
+			}
+			renamed[resource.GetKind()+"/"+resource.GetName()] = true
+			resource.SetName(prefix + resource.GetName())
+		}
+		for _, resource := range resources {
+			for _, reference := range nameReferences[resource.GetKind()] {
+				prefixNameReference(resource.Object, strings.Split(reference.path, "."), reference.kind, prefix, renamed)
+			}
+			if podSpecPath, ok := podSpecPaths[resource.GetKind()]; ok {
+				for _, reference := range podSpecNameReferences {
+					path := strings.Split(podSpecPath+"."+reference.path, ".")
+					prefixNameReference(resource.Object, path, reference.kind, prefix, renamed)
+				}
+			}
+		}
+		return resources, nil
+	})
+}
+
+// Prefixes the name at the path of the value if it references a renamed resource.
+func prefixNameReference(value interface{}, path []string, kind, prefix string, renamed map[string]bool) {
+	fields, ok := value.(map[string]interface{})
+	if !ok || len(path) == 0 {
+		return
+	}
+	if key := strings.TrimSuffix(path[0], "[]"); key != path[0] {
+		items, _ := fields[key].([]interface{})
+		for _, item := range items {
+			prefixNameReference(item, path[1:], kind, prefix, renamed)
+		}
+		return
+	}
+	if len(path) > 1 {
+		prefixNameReference(fields[path[0]], path[1:], kind, prefix, renamed)
+		return
+	}
+	if kind == "" {
+		kind, _ = fields["kind"].(string)
+	}
+	if name, ok := fields[path[0]].(string); ok && renamed[kind+"/"+name] {
+		fields[path[0]] = prefix + name
+	}
+}
+
+func mergeStringMaps(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
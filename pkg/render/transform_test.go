@@ -0,0 +1,212 @@
+package render_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/solo-io/go-utils/installutils/kuberesource"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/service-mesh-hub/pkg/render/validation"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("transformers", func() {
+
+	const manifest = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+  labels:
+    app: app
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: app
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: app
+subjects:
+- kind: ServiceAccount
+  name: app
+  namespace: install
+- kind: ServiceAccount
+  name: other
+  namespace: install
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: things.example.com
+`
+
+	var resources kuberesource.UnstructuredResources
+
+	BeforeEach(func() {
+		var err error
+		resources, err = render.YamlToResources([]byte(manifest))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	transform := func(transformers ...render.Transformer) kuberesource.UnstructuredResources {
+		transformed, err := render.ApplyTransformers(context.TODO(), transformers, render.ValuesInputs{}, resources)
+		Expect(err).NotTo(HaveOccurred())
+		return transformed
+	}
+
+	It("adds common labels and annotations without dropping existing ones", func() {
+		transformed := transform(
+			render.CommonLabels(map[string]string{"team": "mesh"}),
+			render.CommonAnnotations(map[string]string{"owner": "platform"}))
+		for _, resource := range transformed {
+			Expect(resource.GetLabels()).To(HaveKeyWithValue("team", "mesh"))
+			Expect(resource.GetAnnotations()).To(HaveKeyWithValue("owner", "platform"))
+		}
+		Expect(transformed[0].GetLabels()).To(HaveKeyWithValue("app", "app"))
+	})
+
+	It("prefixes names and the role binding references to renamed resources", func() {
+		transformed := transform(render.NamePrefix("prod-"))
+		Expect(transformed[0].GetName()).To(Equal("prod-app"))
+		Expect(transformed[1].GetName()).To(Equal("prod-app"))
+		Expect(transformed[3].GetName()).To(Equal("things.example.com"))
+
+		binding := transformed[2].Object
+		Expect(binding["roleRef"]).To(HaveKeyWithValue("name", "prod-app"))
+		subjects := binding["subjects"].([]interface{})
+		Expect(subjects[0]).To(HaveKeyWithValue("name", "prod-app"))
+		Expect(subjects[1]).To(HaveKeyWithValue("name", "other"))
+	})
+
+	It("prefixes the references of workloads and webhooks to renamed resources, but not namespaces", func() {
+		resources, err := render.YamlToResources([]byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: install
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+  namespace: install
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: install
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: webhook
+  namespace: install
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: install
+spec:
+  template:
+    spec:
+      serviceAccountName: app
+      containers:
+      - name: app
+        envFrom:
+        - configMapRef:
+            name: config
+        env:
+        - name: TOKEN
+          valueFrom:
+            secretKeyRef:
+              name: external
+              key: token
+      volumes:
+      - name: config
+        configMap:
+          name: config
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: app
+webhooks:
+- name: validate.example.com
+  clientConfig:
+    service:
+      name: webhook
+      namespace: install
+`))
+		Expect(err).NotTo(HaveOccurred())
+		transformed, err := render.ApplyTransformers(context.TODO(), []render.Transformer{render.NamePrefix("prod-")},
+			render.ValuesInputs{}, resources)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(transformed[0].GetName()).To(Equal("install"))
+		for _, resource := range transformed[1:] {
+			Expect(resource.GetName()).To(HavePrefix("prod-"))
+		}
+		Expect(transformed[4].GetNamespace()).To(Equal("install"))
+
+		podSpec, _, _ := unstructured.NestedMap(transformed[4].Object, "spec", "template", "spec")
+		Expect(podSpec).To(HaveKeyWithValue("serviceAccountName", "prod-app"))
+		container := podSpec["containers"].([]interface{})[0].(map[string]interface{})
+		Expect(container["envFrom"]).To(ConsistOf(HaveKeyWithValue("configMapRef", HaveKeyWithValue("name", "prod-config"))))
+		Expect(container["env"]).To(ConsistOf(HaveKeyWithValue("valueFrom",
+			HaveKeyWithValue("secretKeyRef", HaveKeyWithValue("name", "external")))))
+		Expect(podSpec["volumes"]).To(ConsistOf(HaveKeyWithValue("configMap", HaveKeyWithValue("name", "prod-config"))))
+
+		webhook := transformed[5].Object["webhooks"].([]interface{})[0].(map[string]interface{})
+		service, _, _ := unstructured.NestedString(webhook, "clientConfig", "service", "name")
+		Expect(service).To(Equal("prod-webhook"))
+	})
+
+	It("applies transformers in order and stops at the first error", func() {
+		var calls []string
+		record := func(name string, err error) render.Transformer {
+			return render.TransformerFunc(func(_ context.Context, _ render.ValuesInputs, resources kuberesource.UnstructuredResources) (kuberesource.UnstructuredResources, error) {
+				calls = append(calls, name)
+				return resources, err
+			})
+		}
+		_, err := render.ApplyTransformers(context.TODO(), []render.Transformer{
+			record("first", nil),
+			record("second", context.Canceled),
+			record("third", nil),
+		}, render.ValuesInputs{}, resources)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(calls).To(Equal([]string{"first", "second"}))
+	})
+
+	It("runs the transformers registered on the manifest renderer", func() {
+		spec := &v1.VersionedApplicationSpec{
+			InstallationSpec: &v1.VersionedApplicationSpec_InlineManifests{
+				InlineManifests: &v1.InlineManifests{Yaml: manifest},
+			},
+		}
+		inputs := render.ValuesInputs{
+			Name:             "app",
+			InstallNamespace: "install",
+			Flavor:           &v1.Flavor{},
+			MeshRef:          core.ResourceRef{Name: "istio", Namespace: "istio-system"},
+		}
+		renderer := render.NewManifestRenderer(validation.NoopValidateResources,
+			render.WithTransformers(render.NamePrefix("prod-"), render.CommonLabels(map[string]string{"team": "mesh"})))
+		rendered, err := renderer.ComputeResourcesForApplication(context.TODO(), inputs, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered).To(HaveLen(4))
+		for _, resource := range rendered {
+			Expect(resource.GetLabels()).To(HaveKeyWithValue("team", "mesh"))
+		}
+	})
+})
@@ -15,12 +15,12 @@ var (
 
 type GetSecretValue func(value *v1.SecretValue) (string, error)
 
+// Only resolves plain text secrets. See SecretResolver for the other types of secrets.
 func PlainTextSecretGetter(value *v1.SecretValue) (string, error) {
 	switch t := value.GetType().(type) {
 	case *v1.SecretValue_PlainText:
 		return t.PlainText, nil
 	default:
-		// TODO support file secrets
 		return "", UnsupportedParamType(t)
 	}
 }
@@ -0,0 +1,145 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	errors "github.com/rotisserie/eris"
+	corev1 "k8s.io/api/core/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	FailedToGetSecretError = func(err error, namespace, name string) error {
+		return errors.Wrapf(err, "failed to get secret %v.%v", namespace, name)
+	}
+
+	FailedToReadSecretSnapshotError = func(err error, path string) error {
+		return errors.Wrapf(err, "failed to read secrets from %v", path)
+	}
+)
+
+// Looks up Kubernetes secrets, either in a cluster or in a snapshot of one.
+type SecretClient interface {
+	GetSecret(namespace, name string) (*corev1.Secret, error)
+}
+
+type clientsetSecretClient struct {
+	clientset kubernetes.Interface
+}
+
+// Returns a client that gets secrets from the cluster of the clientset, or from a fake clientset in tests.
+func NewClientsetSecretClient(clientset kubernetes.Interface) SecretClient {
+	return &clientsetSecretClient{clientset: clientset}
+}
+
+func (c *clientsetSecretClient) GetSecret(namespace, name string) (*corev1.Secret, error) {
+	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, FailedToGetSecretError(err, namespace, name)
+	}
+	return secret, nil
+}
+
+type snapshotSecretClient struct {
+	secrets map[string]*corev1.Secret
+}
+
+// Returns a client that gets secrets from the given ones rather than from a cluster.
+func NewSnapshotSecretClient(secrets []corev1.Secret) SecretClient {
+	client := &snapshotSecretClient{secrets: make(map[string]*corev1.Secret, len(secrets))}
+	for i := range secrets {
+		secret := &secrets[i]
+		client.secrets[secret.Namespace+"/"+secret.Name] = secret
+	}
+	return client
+}
+
+func (c *snapshotSecretClient) GetSecret(namespace, name string) (*corev1.Secret, error) {
+	secret, ok := c.secrets[namespace+"/"+name]
+	if !ok {
+		return nil, FailedToGetSecretError(kubeerrors.NewNotFound(corev1.Resource("secrets"), name), namespace, name)
+	}
+	return secret, nil
+}
+
+// Returns a client that gets secrets from a local YAML file, e.g. the output of `kubectl get secrets -o yaml`.
+func LoadSecretSnapshot(path string) (SecretClient, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, FailedToReadSecretSnapshotError(err, path)
+	}
+	secrets, err := ReadSecrets(data)
+	if err != nil {
+		return nil, FailedToReadSecretSnapshotError(err, path)
+	}
+	return NewSnapshotSecretClient(secrets), nil
+}
+
+// Reads the secrets in YAML or JSON documents, including the items of lists. Other kinds of resources are ignored.
+func ReadSecrets(data []byte) ([]corev1.Secret, error) {
+	var secrets []corev1.Secret
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var doc json.RawMessage
+		if err := decoder.Decode(&doc); err == io.EOF {
+			return secrets, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(doc) == 0 || string(doc) == "null" {
+			continue
+		}
+
+		docSecrets, err := readSecrets(doc, "")
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, docSecrets...)
+	}
+}
+
+// Reads the secrets in the document, which is of the given kind if it does not specify one, like the items of lists.
+func readSecrets(doc json.RawMessage, kind string) ([]corev1.Secret, error) {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(doc, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.Kind != "" {
+		kind = typeMeta.Kind
+	}
+	switch kind {
+	case "Secret":
+		var secret corev1.Secret
+		if err := json.Unmarshal(doc, &secret); err != nil {
+			return nil, err
+		}
+		return []corev1.Secret{secret}, nil
+	case "List", "SecretList":
+		var list struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(doc, &list); err != nil {
+			return nil, err
+		}
+		itemKind := ""
+		if kind == "SecretList" {
+			itemKind = "Secret"
+		}
+		var secrets []corev1.Secret
+		for _, item := range list.Items {
+			itemSecrets, err := readSecrets(item, itemKind)
+			if err != nil {
+				return nil, err
+			}
+			secrets = append(secrets, itemSecrets...)
+		}
+		return secrets, nil
+	}
+	return nil, nil
+}
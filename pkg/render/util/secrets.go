@@ -0,0 +1,149 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	errors "github.com/rotisserie/eris"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+)
+
+var (
+	FailedToReadSecretFileError = func(err error, path string) error {
+		return errors.Wrapf(err, "failed to read secret file %v", path)
+	}
+
+	SecretKeyNotFoundError = func(namespace, name, key string) error {
+		return errors.Errorf("secret %v.%v has no key %v", namespace, name, key)
+	}
+)
+
+// Resolves the values of SECRET params.
+type SecretResolver interface {
+	// Whether the resolver can resolve the value. Resolvers only support some of the types of secret values.
+	Supports(value *v1.SecretValue) bool
+	ResolveSecret(value *v1.SecretValue) (string, error)
+}
+
+type plainTextSecretResolver struct{}
+
+func (plainTextSecretResolver) Supports(value *v1.SecretValue) bool {
+	_, ok := value.GetType().(*v1.SecretValue_PlainText)
+	return ok
+}
+
+func (plainTextSecretResolver) ResolveSecret(value *v1.SecretValue) (string, error) {
+	return PlainTextSecretGetter(value)
+}
+
+// Resolves plain text secrets.
+var PlainTextSecretResolver SecretResolver = plainTextSecretResolver{}
+
+// Resolves secrets from local files, e.g. mounted secrets. A single trailing newline is trimmed from the contents.
+type FileSecretResolver struct{}
+
+func (FileSecretResolver) Supports(value *v1.SecretValue) bool {
+	_, ok := value.GetType().(*v1.SecretValue_FilePath)
+	return ok
+}
+
+func (FileSecretResolver) ResolveSecret(value *v1.SecretValue) (string, error) {
+	path := value.GetFilePath()
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", FailedToReadSecretFileError(err, path)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(contents), "\n"), "\r"), nil
+}
+
+// Resolves Kubernetes secret refs from environment variables, so that CI systems can provide secrets without access to
+// the cluster. The value of the key of the secret is read from an environment variable named after the prefix, the
+// namespace, the name and the key of the ref, in upper case with other characters replaced by underscores, e.g.
+// HUBCTL_SECRET_ISTIO_SYSTEM_CACERTS_ROOT_CERT_PEM for the root-cert.pem key of secret istio-system.cacerts.
+type EnvSecretResolver struct {
+	Prefix string
+}
+
+const DefaultSecretEnvPrefix = "HUBCTL_SECRET_"
+
+var nonAlphanumeric = regexp.MustCompile("[^A-Z0-9]+")
+
+// Returns the environment variable that holds the value of the secret ref.
+func (r EnvSecretResolver) EnvVar(ref *v1.SecretRef) string {
+	parts := []string{ref.GetRef().GetNamespace(), ref.GetRef().GetName(), ref.GetKey()}
+	return r.Prefix + nonAlphanumeric.ReplaceAllString(strings.ToUpper(strings.Join(parts, "_")), "_")
+}
+
+// Supports the secret refs for which the environment variable is set.
+func (r EnvSecretResolver) Supports(value *v1.SecretValue) bool {
+	ref := value.GetSecretRef()
+	if ref == nil {
+		return false
+	}
+	_, ok := os.LookupEnv(r.EnvVar(ref))
+	return ok
+}
+
+func (r EnvSecretResolver) ResolveSecret(value *v1.SecretValue) (string, error) {
+	ref := value.GetSecretRef()
+	secret, ok := os.LookupEnv(r.EnvVar(ref))
+	if !ok {
+		return "", SecretKeyNotFoundError(ref.GetRef().GetNamespace(), ref.GetRef().GetName(), ref.GetKey())
+	}
+	return secret, nil
+}
+
+// Resolves Kubernetes secret refs with the client.
+type KubeSecretResolver struct {
+	Secrets SecretClient
+}
+
+func (r KubeSecretResolver) Supports(value *v1.SecretValue) bool {
+	_, ok := value.GetType().(*v1.SecretValue_SecretRef)
+	return ok
+}
+
+func (r KubeSecretResolver) ResolveSecret(value *v1.SecretValue) (string, error) {
+	ref := value.GetSecretRef()
+	namespace, name, key := ref.GetRef().GetNamespace(), ref.GetRef().GetName(), ref.GetKey()
+	secret, err := r.Secrets.GetSecret(namespace, name)
+	if err != nil {
+		return "", err
+	}
+	if data, ok := secret.Data[key]; ok {
+		return string(data), nil
+	}
+	if data, ok := secret.StringData[key]; ok {
+		return data, nil
+	}
+	return "", SecretKeyNotFoundError(namespace, name, key)
+}
+
+// Resolves each secret with the first of the resolvers that supports it. Plain text secrets are always supported.
+type SecretResolverChain []SecretResolver
+
+func (c SecretResolverChain) Supports(value *v1.SecretValue) bool {
+	return c.resolverFor(value) != nil
+}
+
+func (c SecretResolverChain) ResolveSecret(value *v1.SecretValue) (string, error) {
+	resolver := c.resolverFor(value)
+	if resolver == nil {
+		return "", UnsupportedParamType(value.GetType())
+	}
+	return resolver.ResolveSecret(value)
+}
+
+func (c SecretResolverChain) resolverFor(value *v1.SecretValue) SecretResolver {
+	for _, resolver := range c {
+		if resolver.Supports(value) {
+			return resolver
+		}
+	}
+	if PlainTextSecretResolver.Supports(value) {
+		return PlainTextSecretResolver
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package util_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "github.com/solo-io/service-mesh-hub/api/v1"
+	"github.com/solo-io/service-mesh-hub/pkg/render/util"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("secrets", func() {
+
+	secretRef := func(namespace, name, key string) *v1.SecretValue {
+		return &v1.SecretValue{Type: &v1.SecretValue_SecretRef{SecretRef: &v1.SecretRef{
+			Ref: &core.ResourceRef{Namespace: namespace, Name: name},
+			Key: key,
+		}}}
+	}
+	plainText := &v1.SecretValue{Type: &v1.SecretValue_PlainText{PlainText: "plain"}}
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "secrets-")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("reads file secrets, trimming the trailing newline", func() {
+		path := filepath.Join(dir, "token")
+		Expect(ioutil.WriteFile(path, []byte("s3cr3t\n"), 0600)).To(Succeed())
+		value := &v1.SecretValue{Type: &v1.SecretValue_FilePath{FilePath: path}}
+
+		resolver := util.FileSecretResolver{}
+		Expect(resolver.Supports(value)).To(BeTrue())
+		Expect(resolver.Supports(plainText)).To(BeFalse())
+		Expect(resolver.ResolveSecret(value)).To(Equal("s3cr3t"))
+
+		_, err := resolver.ResolveSecret(&v1.SecretValue{Type: &v1.SecretValue_FilePath{FilePath: filepath.Join(dir, "missing")}})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reads secret refs from environment variables", func() {
+		resolver := util.EnvSecretResolver{Prefix: util.DefaultSecretEnvPrefix}
+		value := secretRef("istio-system", "cacerts", "root-cert.pem")
+		Expect(resolver.EnvVar(value.GetSecretRef())).To(Equal("HUBCTL_SECRET_ISTIO_SYSTEM_CACERTS_ROOT_CERT_PEM"))
+		Expect(resolver.Supports(value)).To(BeFalse())
+
+		os.Setenv("HUBCTL_SECRET_ISTIO_SYSTEM_CACERTS_ROOT_CERT_PEM", "cert")
+		defer os.Unsetenv("HUBCTL_SECRET_ISTIO_SYSTEM_CACERTS_ROOT_CERT_PEM")
+		Expect(resolver.Supports(value)).To(BeTrue())
+		Expect(resolver.ResolveSecret(value)).To(Equal("cert"))
+	})
+
+	It("reads secret refs from a clientset", func() {
+		clientset := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "cacerts"},
+			Data:       map[string][]byte{"root-cert.pem": []byte("cert")},
+		})
+		resolver := util.KubeSecretResolver{Secrets: util.NewClientsetSecretClient(clientset)}
+		Expect(resolver.ResolveSecret(secretRef("istio-system", "cacerts", "root-cert.pem"))).To(Equal("cert"))
+
+		_, err := resolver.ResolveSecret(secretRef("istio-system", "cacerts", "ca-key.pem"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(util.SecretKeyNotFoundError("istio-system", "cacerts", "ca-key.pem").Error()))
+
+		_, err = resolver.ResolveSecret(secretRef("istio-system", "missing", "root-cert.pem"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed to get secret istio-system.missing"))
+	})
+
+	It("reads secret refs from a snapshot", func() {
+		path := filepath.Join(dir, "secrets.yaml")
+		Expect(ioutil.WriteFile(path, []byte(`apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: cacerts
+    namespace: istio-system
+  data:
+    root-cert.pem: Y2VydA==
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config
+    namespace: istio-system
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: token
+  namespace: default
+stringData:
+  token: s3cr3t
+`), 0600)).To(Succeed())
+		client, err := util.LoadSecretSnapshot(path)
+		Expect(err).NotTo(HaveOccurred())
+		resolver := util.KubeSecretResolver{Secrets: client}
+		Expect(resolver.ResolveSecret(secretRef("istio-system", "cacerts", "root-cert.pem"))).To(Equal("cert"))
+		Expect(resolver.ResolveSecret(secretRef("default", "token", "token"))).To(Equal("s3cr3t"))
+		_, err = resolver.ResolveSecret(secretRef("istio-system", "config", "key"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("resolves secrets with the first resolver that supports them", func() {
+		os.Setenv("TEST_SECRET_DEFAULT_TOKEN_TOKEN", "from-env")
+		defer os.Unsetenv("TEST_SECRET_DEFAULT_TOKEN_TOKEN")
+		clientset := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "token"},
+			Data:       map[string][]byte{"token": []byte("from-kube"), "other": []byte("other-from-kube")},
+		})
+		resolver := util.SecretResolverChain{
+			util.EnvSecretResolver{Prefix: "TEST_SECRET_"},
+			util.KubeSecretResolver{Secrets: util.NewClientsetSecretClient(clientset)},
+		}
+		Expect(resolver.ResolveSecret(secretRef("default", "token", "token"))).To(Equal("from-env"))
+		Expect(resolver.ResolveSecret(secretRef("default", "token", "other"))).To(Equal("other-from-kube"))
+		Expect(resolver.ResolveSecret(plainText)).To(Equal("plain"))
+
+		_, err := util.SecretResolverChain{}.ResolveSecret(secretRef("default", "token", "token"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("resolves the defaults of secret params", func() {
+		value := &v1.ParameterValue{Type: &v1.ParameterValue_SecretValue{SecretValue: secretRef("default", "token", "token")}}
+		clientset := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "token"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		})
+		resolver := util.SecretResolverChain{util.KubeSecretResolver{Secrets: util.NewClientsetSecretClient(clientset)}}
+		Expect(util.ParamValueToString(value, resolver.ResolveSecret)).To(Equal("s3cr3t"))
+
+		_, err := util.ParamValueToString(value, util.PlainTextSecretGetter)
+		Expect(err).To(HaveOccurred())
+	})
+})
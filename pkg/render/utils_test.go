@@ -3,11 +3,14 @@ package render_test
 import (
 	"context"
 
+	"github.com/solo-io/go-utils/installutils/helmchart"
 	"github.com/solo-io/service-mesh-hub/pkg/render/validation"
 	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
 
 	"github.com/solo-io/service-mesh-hub/pkg/render"
+	"github.com/solo-io/service-mesh-hub/pkg/render/util"
 
+	"github.com/gogo/protobuf/types"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -39,6 +42,43 @@ var _ = Describe("utils", func() {
 		})
 	})
 
+	Context("layer combinations", func() {
+		It("combines the options of every layer, leaving optional layers out", func() {
+			flavor := &v1.Flavor{
+				CustomizationLayers: []*v1.Layer{
+					{Id: "size", Options: []*v1.LayerOption{{Id: "small"}, {Id: "large"}}},
+					{Id: "tracing", Optional: true, Options: []*v1.LayerOption{{Id: "jaeger"}}},
+				},
+			}
+			Expect(render.GetLayerCombinations(flavor)).To(Equal([][]render.LayerInput{
+				{{LayerId: "size", OptionId: "small"}},
+				{{LayerId: "size", OptionId: "small"}, {LayerId: "tracing", OptionId: "jaeger"}},
+				{{LayerId: "size", OptionId: "large"}},
+				{{LayerId: "size", OptionId: "large"}, {LayerId: "tracing", OptionId: "jaeger"}},
+			}))
+		})
+
+		It("returns a single empty combination for flavors without layers", func() {
+			Expect(render.GetLayerCombinations(&v1.Flavor{})).To(Equal([][]render.LayerInput{{}}))
+		})
+
+		It("collects the default params of the selected options", func() {
+			stringValue := func(s string) *v1.ParameterValue {
+				return &v1.ParameterValue{Type: &v1.ParameterValue_StringValue{StringValue: s}}
+			}
+			version := &v1.VersionedApplicationSpec{Parameters: []*v1.Parameter{{Name: "a", Default: stringValue("version")}}}
+			flavor := &v1.Flavor{
+				Parameters: []*v1.Parameter{{Name: "b", Default: stringValue("flavor")}, {Name: "c"}},
+				CustomizationLayers: []*v1.Layer{{Id: "layer", Options: []*v1.LayerOption{
+					{Id: "option", Parameters: []*v1.Parameter{{Name: "a", Default: stringValue("option")}}},
+				}}},
+			}
+			params, err := render.GetDefaultParams(version, flavor, []render.LayerInput{{LayerId: "layer", OptionId: "option"}}, util.PlainTextSecretResolver)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params).To(Equal(map[string]string{"a": "option", "b": "flavor"}))
+		})
+	})
+
 	Context("params", func() {
 		It("works", func() {
 			input := make(map[string]string)
@@ -242,6 +282,42 @@ var _ = Describe("utils", func() {
 			Expect(render.ComputeValueOverrides(context.TODO(), inputs)).To(BeEquivalentTo(expected))
 		})
 
+		It("renders templates in layer values", func() {
+			flavor := &v1.Flavor{
+				CustomizationLayers: []*v1.Layer{{
+					Id: "certs",
+					Options: []*v1.LayerOption{{
+						Id:         "custom",
+						HelmValues: "security:\n  certNamespace: {{ .MeshRef.Namespace }}\n  secret: {{ .Params.certSecret | quote }}\n",
+					}},
+				}},
+			}
+			inputs := render.ValuesInputs{
+				Flavor:  flavor,
+				Layers:  []render.LayerInput{{LayerId: "certs", OptionId: "custom"}},
+				MeshRef: core.ResourceRef{Name: "istio", Namespace: "istio-system"},
+				Params:  map[string]string{"certSecret": "my-cert"},
+			}
+			expected := "certSecret: my-cert\nsecurity:\n  certNamespace: istio-system\n  secret: my-cert\n"
+			Expect(render.ComputeValueOverrides(context.TODO(), inputs)).To(BeEquivalentTo(expected))
+			Expect(flavor.CustomizationLayers[0].Options[0].HelmValues).To(ContainSubstring("{{ .MeshRef.Namespace }}"))
+		})
+
+		It("errors on invalid templates in layer values", func() {
+			inputs := render.ValuesInputs{
+				Flavor: &v1.Flavor{
+					CustomizationLayers: []*v1.Layer{{
+						Id:      "certs",
+						Options: []*v1.LayerOption{{Id: "custom", HelmValues: "secret: {{ .Params.certSecret"}},
+					}},
+				},
+				Layers: []render.LayerInput{{LayerId: "certs", OptionId: "custom"}},
+			}
+			_, err := render.ComputeValueOverrides(context.TODO(), inputs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("layer option certs/custom"))
+		})
+
 		It("handles empty case", func() {
 			inputs := render.ValuesInputs{}
 			expected := ""
@@ -429,6 +505,172 @@ var _ = Describe("utils", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("test"))
 		})
+
+		It("lists the required params that have no value", func() {
+			inputs := render.ValuesInputs{
+				Flavor: &v1.Flavor{
+					CustomizationLayers: []*v1.Layer{{
+						Id: "a",
+						Options: []*v1.LayerOption{
+							{Id: "1", Parameters: []*v1.Parameter{{Name: "layer", Required: true}}},
+							{Id: "2", Parameters: []*v1.Parameter{{Name: "other", Required: true}}},
+						},
+					}},
+					Parameters: []*v1.Parameter{{Name: "foo", Required: true}},
+				},
+				Layers: []render.LayerInput{{LayerId: "a", OptionId: "1"}},
+				Params: map[string]string{"foo": "bar"},
+			}
+			version := &v1.VersionedApplicationSpec{
+				Parameters: []*v1.Parameter{{Name: "bar", Required: true}, {Name: "optional"}},
+			}
+			Expect(render.GetMissingRequiredParams(inputs, version)).To(Equal([]string{"bar", "layer"}))
+
+			inputs.Params["bar"], inputs.Params["layer"] = "baz", "value"
+			Expect(render.GetMissingRequiredParams(inputs, version)).To(BeEmpty())
+		})
+	})
+
+	Context("validate param types", func() {
+		param := func(paramType v1.ParameterType) *v1.Parameter {
+			return &v1.Parameter{Name: "param", Type: paramType}
+		}
+
+		It("accepts values matching the type", func() {
+			Expect(render.ValidateParamValue(param(v1.ParameterType_INT), "-42")).To(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_FLOAT), "0.5")).To(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_BOOL), "True")).To(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_DATE), "2020-01-31")).To(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_DATE), "2020-01-31T10:00:00Z")).To(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_STRING), "anything")).To(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_SECRET), "anything")).To(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_INT), "")).To(Succeed())
+		})
+
+		It("rejects values that do not match the type", func() {
+			err := render.ValidateParamValue(param(v1.ParameterType_INT), "abc")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(render.InvalidParamValueError("param", v1.ParameterType_INT, "abc").Error()))
+			Expect(render.ValidateParamValue(param(v1.ParameterType_INT), "1.5")).NotTo(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_FLOAT), "one")).NotTo(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_BOOL), "yes please")).NotTo(Succeed())
+			Expect(render.ValidateParamValue(param(v1.ParameterType_DATE), "tomorrow")).NotTo(Succeed())
+		})
+
+		It("validates the rendered params declared by the spec, flavor and layers", func() {
+			flavor := &v1.Flavor{
+				Parameters: []*v1.Parameter{{Name: "replicas", Type: v1.ParameterType_INT}},
+				CustomizationLayers: []*v1.Layer{{
+					Id:      "tls",
+					Options: []*v1.LayerOption{{Id: "on", Parameters: []*v1.Parameter{{Name: "strict", Type: v1.ParameterType_BOOL}}}},
+				}},
+			}
+			inputs := render.ValuesInputs{
+				Flavor: flavor,
+				Layers: []render.LayerInput{{LayerId: "tls", OptionId: "on"}},
+				Params: map[string]string{"replicas": "3", "strict": "maybe"},
+			}
+			err := render.ValidateParamTypes(inputs, &v1.VersionedApplicationSpec{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("strict"))
+
+			inputs.Params["strict"] = "false"
+			Expect(render.ValidateParamTypes(inputs, &v1.VersionedApplicationSpec{})).To(Succeed())
+		})
+
+		It("validates params after rendering their templates", func() {
+			spec := &v1.VersionedApplicationSpec{
+				InstallationSpec: &v1.VersionedApplicationSpec_InlineManifests{
+					InlineManifests: &v1.InlineManifests{Yaml: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n"},
+				},
+				Parameters: []*v1.Parameter{
+					{Name: "port", Type: v1.ParameterType_INT},
+					{Name: "adminPort", Type: v1.ParameterType_INT},
+				},
+			}
+			inputs := render.ValuesInputs{
+				Flavor: &v1.Flavor{},
+				Params: map[string]string{"port": "8080", "adminPort": "{{ .Params.port }}"},
+			}
+			renderer := render.NewManifestRenderer(validation.NoopValidateResources)
+			_, err := renderer.ComputeResourcesForApplication(context.TODO(), inputs, spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			inputs.Params = map[string]string{"port": "http", "adminPort": "{{ .Params.port }}"}
+			_, err = renderer.ComputeResourcesForApplication(context.TODO(), inputs, spec)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("adminPort must be of type INT"))
+		})
+	})
+
+	Context("validate param constraints", func() {
+		It("accepts only the allowed values", func() {
+			param := &v1.Parameter{Name: "meshProvider", AllowedValues: []string{"istio", "linkerd"}}
+			Expect(render.ValidateParamValue(param, "linkerd")).To(Succeed())
+			Expect(render.ValidateParamValue(param, "")).To(Succeed())
+			err := render.ValidateParamValue(param, "consul")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(render.ParamValueNotAllowedError("meshProvider", "consul", param.AllowedValues).Error()))
+		})
+
+		It("matches values against the pattern", func() {
+			param := &v1.Parameter{Name: "name", Pattern: "^[a-z0-9-]+$"}
+			Expect(render.ValidateParamValue(param, "my-app")).To(Succeed())
+			Expect(render.ValidateParamValue(param, "My_App")).NotTo(Succeed())
+
+			param.Pattern = "[a-z"
+			err := render.ValidateParamValue(param, "my-app")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid pattern for parameter name"))
+		})
+
+		It("checks the range of numeric values", func() {
+			param := &v1.Parameter{
+				Name:    "replicas",
+				Type:    v1.ParameterType_INT,
+				Minimum: &types.DoubleValue{Value: 1},
+				Maximum: &types.DoubleValue{Value: 5},
+			}
+			Expect(render.ValidateParamValue(param, "1")).To(Succeed())
+			Expect(render.ValidateParamValue(param, "5")).To(Succeed())
+			err := render.ValidateParamValue(param, "0")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(render.ParamValueOutOfRangeError("replicas", "0", "at least 1").Error()))
+			Expect(render.ValidateParamValue(param, "6")).NotTo(Succeed())
+
+			param.Type = v1.ParameterType_FLOAT
+			param.Maximum = nil
+			Expect(render.ValidateParamValue(param, "1000.5")).To(Succeed())
+			Expect(render.ValidateParamValue(param, "0.5")).NotTo(Succeed())
+		})
+
+		It("checks the length of values", func() {
+			param := &v1.Parameter{
+				Name:      "prefix",
+				MinLength: &types.UInt32Value{Value: 2},
+				MaxLength: &types.UInt32Value{Value: 4},
+			}
+			Expect(render.ValidateParamValue(param, "ab")).To(Succeed())
+			Expect(render.ValidateParamValue(param, "äöüß")).To(Succeed())
+			Expect(render.ValidateParamValue(param, "a")).NotTo(Succeed())
+			Expect(render.ValidateParamValue(param, "abcde")).NotTo(Succeed())
+		})
+
+		It("enforces constraints when validating inputs, leaving templates for later", func() {
+			spec := v1.VersionedApplicationSpec{
+				Parameters: []*v1.Parameter{{Name: "meshProvider", AllowedValues: []string{"istio", "linkerd"}}},
+			}
+			inputs := render.ValuesInputs{
+				Flavor: &v1.Flavor{},
+				Params: map[string]string{"meshProvider": "consul"},
+			}
+			err := render.ValidateInputs(inputs, spec, validation.NoopValidateResources)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("meshProvider must be one of istio, linkerd"))
+
+			inputs.Params["meshProvider"] = "{{ .Params.other }}"
+			Expect(render.ValidateInputs(inputs, spec, validation.NoopValidateResources)).To(Succeed())
+		})
 	})
 
 	Context("render templates in input values", func() {
@@ -465,5 +707,93 @@ var _ = Describe("utils", func() {
 			Expect(result).To(BeEquivalentTo(expected))
 		})
 
+		It("provides the sprig functions", func() {
+			result, err := render.ExecInputValuesTemplates(render.ValuesInputs{
+				InstallNamespace:  "Test-NS",
+				SpecDefinedValues: "namespace: {{ lower .InstallNamespace | quote }}\nmesh: {{ .MeshRef.Name | default \"istio\" }}\n",
+				Params:            map[string]string{"token": "{{ b64enc \"secret\" }}"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.SpecDefinedValues).To(Equal("namespace: \"test-ns\"\nmesh: istio\n"))
+			Expect(result.Params).To(HaveKeyWithValue("token", "c2VjcmV0"))
+		})
+
+		It("returns an error naming the malformed field", func() {
+			_, err := render.ExecInputValuesTemplates(render.ValuesInputs{UserDefinedValues: "key: {{ .Name"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("user values"))
+
+			_, err = render.ExecInputValuesTemplates(render.ValuesInputs{
+				Params: map[string]string{"valid": "value", "broken": "{{ required \"broken is required\" .Name }}"},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("parameter broken"))
+			Expect(err.Error()).To(ContainSubstring("broken is required"))
+		})
+
+		It("renders params after the params they reference", func() {
+			for i := 0; i < 10; i++ {
+				result, err := render.ExecInputValuesTemplates(render.ValuesInputs{
+					InstallNamespace:  "test-ns",
+					SpecDefinedValues: "endpoint: {{ .Params.url }}",
+					Params: map[string]string{
+						"url":    "https://{{ .Params.host }}:{{ index .Params \"port\" }}",
+						"host":   "{{ .Params.prefix }}.{{ .InstallNamespace }}",
+						"prefix": "api",
+						"port":   "8443",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Params).To(HaveKeyWithValue("url", "https://api.test-ns:8443"))
+				Expect(result.SpecDefinedValues).To(Equal("endpoint: https://api.test-ns:8443"))
+			}
+		})
+
+		It("returns an error naming a cycle of params", func() {
+			_, err := render.ExecInputValuesTemplates(render.ValuesInputs{
+				Params: map[string]string{
+					"a":           "{{ .Params.b }}",
+					"b":           "{{ if true }}{{ $.Params.c }}{{ end }}",
+					"c":           "{{ .Params.a }}",
+					"independent": "value",
+				},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("a -> b -> c -> a"))
+		})
+
+		It("does not expose the environment", func() {
+			_, err := render.ExecInputValuesTemplates(render.ValuesInputs{SpecDefinedValues: "home: {{ env \"HOME\" }}"})
+			Expect(err).To(HaveOccurred())
+		})
+
+	})
+
+	Context("render templates in manifests", func() {
+
+		inputs := render.ValuesInputs{
+			Name:             "bookinfo",
+			InstallNamespace: "test-ns",
+			MeshRef:          core.ResourceRef{Namespace: "mesh-ns", Name: "my-mesh"},
+			Params:           map[string]string{"replicas": "3"},
+		}
+
+		It("correctly renders manifests", func() {
+			manifests := helmchart.Manifests{{
+				Name:    "deployment.yaml",
+				Content: "name: {{ .Name }}\nnamespace: {{ .InstallNamespace }}\nmesh: {{ .MeshRef.Name }}\nreplicas: {{ .Params.replicas }}\n",
+			}}
+			result, err := render.ExecManifestTemplates(manifests, inputs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+			Expect(result[0].Name).To(Equal("deployment.yaml"))
+			Expect(result[0].Content).To(Equal("name: bookinfo\nnamespace: test-ns\nmesh: my-mesh\nreplicas: 3\n"))
+		})
+
+		It("errors on invalid templates", func() {
+			manifests := helmchart.Manifests{{Name: "broken.yaml", Content: "name: {{ .Name "}}
+			_, err := render.ExecManifestTemplates(manifests, inputs)
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })